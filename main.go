@@ -13,16 +13,31 @@ following Go conventions and best practices.
 package main
 
 import (
+	"bufio"
+	"compress/gzip"
+	"encoding/base64"
 	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"html"
 	"io"
+	"math"
+	"math/rand"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/BurntSushi/toml"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
 	"gopkg.in/yaml.v3"
 )
 
@@ -49,6 +64,12 @@ const (
 
 	// TimestampColumn represents the timestamp column identifier.
 	TimestampColumn = "timestamp"
+
+	// SubtitleColumn is the column shown as a secondary label under the event title.
+	SubtitleColumn = "subtitle"
+
+	// DefaultWrapWidth is the default number of characters per line for columns with wrap enabled.
+	DefaultWrapWidth = 30
 )
 
 // Global debug flag.
@@ -57,6 +78,49 @@ var debugMode bool
 // Global variable to store optimized callout lengths.
 var globalOptimizedCallouts []int
 
+// Global layout manifest; nil unless --manifest was given.
+var globalManifest *LayoutManifest
+
+// ManifestElement describes one drawn element in a layout manifest.
+//   - "timeline" and "callout" lines run from (x, y) to (x2, y2)
+//   - "marker" is centred on (x, y) with the marker's bounding width and height
+//   - "text" is anchored at its horizontal centre x and first-line baseline y, with
+//     the estimated width and height of all of its lines
+type ManifestElement struct {
+	Type   string `json:"type"`
+	X      int    `json:"x"`
+	Y      int    `json:"y"`
+	X2     int    `json:"x2,omitempty"`
+	Y2     int    `json:"y2,omitempty"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// LayoutManifest is a machine-readable list of the elements drawn into the SVG, in drawing
+// order, so layouts can be compared structurally in tests instead of pixel by pixel.
+type LayoutManifest struct {
+	Width    int               `json:"width"`
+	Height   int               `json:"height"`
+	Elements []ManifestElement `json:"elements"`
+}
+
+// recordManifest appends an element to the global manifest when one is being collected.
+func recordManifest(element ManifestElement) {
+	if globalManifest != nil {
+		globalManifest.Elements = append(globalManifest.Elements, element)
+	}
+}
+
+// writeManifestFile writes the layout manifest to outputPath as indented JSON.
+func writeManifestFile(outputPath string, manifest *LayoutManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(outputPath, append(data, '\n'), 0600)
+}
+
 // debugPrintf prints debug messages when debug mode is enabled.
 func debugPrintf(format string, args ...interface{}) {
 	if debugMode {
@@ -64,10 +128,29 @@ func debugPrintf(format string, args ...interface{}) {
 	}
 }
 
+// debugSnippet returns the first 30 characters of text for debug messages, cutting on a rune
+// boundary so multi-byte characters are never split.
+func debugSnippet(text string) string {
+	runes := []rune(text)
+	return string(runes[:minInt(30, len(runes))])
+}
+
 // TimelineEvent represents a single event on the timeline with flexible data
 type TimelineEvent struct {
-	Timestamp time.Time
-	Data      map[string]string // Flexible data storage for any columns
+	Timestamp    time.Time
+	EndTimestamp time.Time         // Optional end time for duration bars (zero when not provided)
+	Data         map[string]string // Flexible data storage for any columns
+	Highlighted  bool              // Drawn with an emphasized marker and bold text (columns.highlight_column or --highlight)
+	Future       bool              // After the timeline.future_reference_time, drawn in the timeline.future_style (set only when timeline.distinguish_future is on)
+	MergedCount  int               // Number of events merged into this one by timeline.merge_distance (0 for ordinary events)
+	TextScale    float64           // Extra font size multiplier set by timeline.edge_overflow "shrink" to keep the text on the canvas (0 = 1.0)
+	NumericTime  float64           // Value of the time column under columns.numeric_time; Timestamp then holds its position mapped onto a synthetic time axis
+	Number       int               // 1-based chronological number shown before the title (set only when timeline.number_events is on)
+}
+
+// HasDuration reports whether the event has a usable end time after its start
+func (e TimelineEvent) HasDuration() bool {
+	return !e.EndTimestamp.IsZero() && e.EndTimestamp.After(e.Timestamp)
 }
 
 // GetDisplayText returns the text for a given display element
@@ -81,12 +164,99 @@ func (e TimelineEvent) GetDisplayText(elementName string) string {
 
 // ColumnStyle defines the styling for a specific column when using detailed column configuration
 type ColumnStyle struct {
-	Name       string `yaml:"name"`        // Column name from CSV header (case-insensitive matching)
-	FontFamily string `yaml:"font_family"` // Font family for this column (e.g., "Arial, sans-serif", overrides global font.family)
-	FontSize   int    `yaml:"font_size"`   // Font size in pixels for this column (overrides global font.size)
-	FontWeight string `yaml:"font_weight"` // Font weight: "normal", "bold", "bolder", "lighter", or numeric values
-	Color      string `yaml:"color"`       // Text color for this column (hex color code, overrides global colors)
-	CSSClass   string `yaml:"css_class"`   // Custom CSS class name for advanced styling (optional)
+	Name             string `yaml:"name"`              // Column name from CSV header (case-insensitive matching)
+	FontFamily       string `yaml:"font_family"`       // Font family for this column (e.g., "Arial, sans-serif", overrides global font.family)
+	FontSize         int    `yaml:"font_size"`         // Font size in pixels for this column (overrides global font.size)
+	FontWeight       string `yaml:"font_weight"`       // Font weight: "normal", "bold", "bolder", "lighter", or numeric values
+	Color            string `yaml:"color"`             // Text color for this column (hex color code, overrides global colors)
+	CSSClass         string `yaml:"css_class"`         // Custom CSS class name for advanced styling (optional)
+	NumberFormat     string `yaml:"number_format"`     // Formatting for numeric values: "grouped" (1,234,567), "0.0k" (1.2M), or "bytes" (1.2 MB); empty leaves values unchanged
+	Wrap             bool   `yaml:"wrap"`              // Wrap long values of this column onto multiple lines
+	WrapWidth        int    `yaml:"wrap_width"`        // Maximum characters per line when wrapping (defaults to 30)
+	MaxLines         int    `yaml:"max_lines"`         // Maximum wrapped lines; extra text is cut and the last line ends with "..." (0 = unlimited)
+	SplitOn          string `yaml:"split_on"`          // Delimiter splitting multi-value cells such as tag lists onto separate lines, e.g. "," or "|" (empty = single value)
+	TextDecoration   string `yaml:"text_decoration"`   // SVG text-decoration for this column: "underline", "line-through", or "overline" (empty = none)
+	DecorationColumn string `yaml:"decoration_column"` // Optional CSV column that turns text_decoration on per event, e.g. a "cancelled" flag; values other than empty, "0", "false", or "no" apply it
+}
+
+// TitleFont defines the heading typography used for event titles and subtitles.
+// Empty fields fall back to values derived from the global font and colors.
+type TitleFont struct {
+	Family string `yaml:"family"` // Font family for titles (defaults to font.family)
+	Size   int    `yaml:"size"`   // Font size for titles in pixels (defaults to font.size + 2); subtitles use 2px less
+	Weight string `yaml:"weight"` // Font weight for titles (defaults to "bold" in the title CSS class)
+	Color  string `yaml:"color"`  // Text color for titles (defaults to colors.text)
+}
+
+// ColumnCandidates lists CSV column names to try in order; the first one present in the
+// header is used. In configuration files it may be a single name or a list of names.
+type ColumnCandidates []string
+
+// UnmarshalYAML accepts either a scalar column name or a sequence of candidate names
+func (c *ColumnCandidates) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*c = ColumnCandidates{value.Value}
+		return nil
+	}
+	var names []string
+	if err := value.Decode(&names); err != nil {
+		return err
+	}
+	*c = names
+	return nil
+}
+
+// String returns the candidates as a comma-separated list for messages
+func (c ColumnCandidates) String() string {
+	return strings.Join(c, ", ")
+}
+
+// Contains reports whether name matches one of the candidates (case-insensitive)
+func (c ColumnCandidates) Contains(name string) bool {
+	for _, candidate := range c {
+		if strings.EqualFold(strings.TrimSpace(candidate), strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ShapeLegendEntry is one row of the shape legend: a sample marker and what it means.
+// Samples are drawn with the event_marker settings, so only the shape differs.
+type ShapeLegendEntry struct {
+	Shape string `yaml:"shape"` // Marker shape: "circle", "triangle", "square", or "diamond"
+	Label string `yaml:"label"` // Description shown next to the sample marker
+}
+
+// GroupBracket annotates a phase of the timeline: a bracket spanning the x range from
+// StartTime to EndTime with a label centred above it.
+type GroupBracket struct {
+	StartTime string `yaml:"start_time"` // Start of the bracketed span, in any supported timestamp format
+	EndTime   string `yaml:"end_time"`   // End of the bracketed span, in any supported timestamp format
+	Label     string `yaml:"label"`      // Text centred above the bracket
+}
+
+// ReferenceMarker is a labelled vertical line at a fixed time, such as a release or code
+// freeze. Reference markers are drawn behind the events and take no part in their layout.
+type ReferenceMarker struct {
+	Time  string `yaml:"time"`  // Time of the line, in any supported timestamp format
+	Label string `yaml:"label"` // Text drawn rotated along the top of the line (optional)
+	Color string `yaml:"color"` // Line and label color (empty = colors.timeline)
+	Style string `yaml:"style"` // Line style: "solid" (default), "dashed", or "dotted"
+}
+
+// CategoryStyle assigns a color to one value of the columns.category_column, used for the
+// timeline.category_stripes rule beside the text of events in that category.
+type CategoryStyle struct {
+	Name  string `yaml:"name"`  // Category value, matched case-insensitively
+	Color string `yaml:"color"` // Stripe color for events in this category (hex color code)
+}
+
+// EventIcon maps a value of columns.icon_column to an image drawn in place of the event
+// marker.
+type EventIcon struct {
+	Value string `yaml:"value"` // Icon column value, matched case-insensitively
+	Image string `yaml:"image"` // Image file path, relative to the config file, or URL (http, https, or data) used as the <image> href
 }
 
 // Config represents the complete configuration for SVG timeline generation.
@@ -104,44 +274,146 @@ type ColumnStyle struct {
 //   - For detailed styling: Set columns.use_detailed_styling = true and define detailed_columns
 type Config struct {
 	Font struct {
-		Family string `yaml:"family"` // Font family for all text elements (e.g., "Arial, sans-serif")
-		Size   int    `yaml:"size"`   // Base font size in pixels for text elements
+		Family string  `yaml:"family"` // Font family for all text elements (e.g., "Arial, sans-serif")
+		Size   int     `yaml:"size"`   // Base font size in pixels for text elements
+		Scale  float64 `yaml:"scale"`  // Multiplier applied to every font size (font, layout.title_font, detailed_columns) at load time, e.g. 1.5 for projectors; layout is unchanged (0 = 1.0)
 	} `yaml:"font"`
 	Colors struct {
-		Background string `yaml:"background"` // SVG background color (hex color code, e.g., "#ffffff")
-		Timeline   string `yaml:"timeline"`   // Color of the main timeline line (hex color code)
-		Events     string `yaml:"events"`     // Color of event markers (hex color code)
-		Text       string `yaml:"text"`       // Color of title and main text (hex color code)
-		Notes      string `yaml:"notes"`      // Color of notes text (hex color code)
+		Background         string   `yaml:"background"`          // SVG background color (hex color code, e.g., "#ffffff")
+		Timeline           string   `yaml:"timeline"`            // Color of the main timeline line (hex color code)
+		Events             string   `yaml:"events"`              // Color of event markers (hex color code)
+		Text               string   `yaml:"text"`                // Color of title and main text (hex color code)
+		Notes              string   `yaml:"notes"`               // Color of notes text (hex color code)
+		DurationBar        string   `yaml:"duration_bar"`        // Fill color of duration bars (hex color code)
+		EventTick          string   `yaml:"event_tick"`          // Color of timeline.event_ticks marks (hex color code, empty = timeline color)
+		Future             string   `yaml:"future"`              // Background band color behind future events for timeline.future_style "band" or "both" (hex color code, defaults to "#eef3fb")
+		Progress           string   `yaml:"progress"`            // Color of the elapsed part of the timeline line for timeline.show_progress (hex color code, defaults to "#34a853")
+		Highlight          string   `yaml:"highlight"`           // Ring color around highlighted event markers (hex color code, defaults to "#e53935")
+		TimelineGradient   []string `yaml:"timeline_gradient"`   // Two colors for a left-to-right linear gradient along the timeline line instead of the solid timeline color (empty = solid)
+		BackgroundGradient []string `yaml:"background_gradient"` // Two colors for a top-to-bottom linear gradient background instead of the solid background color (empty = solid)
+		Weekend            string   `yaml:"weekend"`             // Fill color of weekend and holiday shading bands (hex color code, defaults to "#f0f0f0")
+		AutoContrast       bool     `yaml:"auto_contrast"`       // Replace the text, notes, timeline (line, callouts, and axis), event tick, and marker colors with black or white, whichever is more readable on the background
 	} `yaml:"colors"`
 	Layout struct {
-		Width        int `yaml:"width"`         // Total SVG width in pixels
-		Height       int `yaml:"height"`        // Total SVG height in pixels
-		MarginTop    int `yaml:"margin_top"`    // Top margin in pixels
-		MarginBottom int `yaml:"margin_bottom"` // Bottom margin in pixels
-		MarginLeft   int `yaml:"margin_left"`   // Left margin in pixels
-		MarginRight  int `yaml:"margin_right"`  // Right margin in pixels
-		EventRadius  int `yaml:"event_radius"`  // Radius of event markers in pixels (deprecated, use EventMarker.Size)
-		EventSpacing int `yaml:"event_spacing"` // Vertical spacing from timeline to text in pixels
+		Width             int       `yaml:"width"`               // Total SVG width in pixels
+		Height            int       `yaml:"height"`              // Total SVG height in pixels
+		MarginTop         int       `yaml:"margin_top"`          // Top margin in pixels
+		MarginBottom      int       `yaml:"margin_bottom"`       // Bottom margin in pixels
+		MarginLeft        int       `yaml:"margin_left"`         // Left margin in pixels
+		MarginRight       int       `yaml:"margin_right"`        // Right margin in pixels
+		EventRadius       int       `yaml:"event_radius"`        // Radius of event markers in pixels (deprecated, use EventMarker.Size)
+		EventSpacing      int       `yaml:"event_spacing"`       // Vertical spacing from timeline to text in pixels
+		Watermark         string    `yaml:"watermark"`           // Diagonal watermark text drawn across the canvas (e.g., "DRAFT"); empty disables it
+		WatermarkOpacity  float64   `yaml:"watermark_opacity"`   // Opacity of the watermark text (0-1, defaults to 0.1)
+		WatermarkPosition string    `yaml:"watermark_position"`  // Draw the watermark "behind" (default) or "above" the timeline content
+		TextReserveTop    int       `yaml:"text_reserve_top"`    // Space in pixels kept free for event text when limiting callouts above the timeline (0 = measured from the event text)
+		TextReserveBottom int       `yaml:"text_reserve_bottom"` // Space in pixels kept free for event text when limiting callouts below the timeline (0 = measured from the event text)
+		AutoWidth         bool      `yaml:"auto_width"`          // Size the SVG width so every event gets min_event_spacing pixels (the configured width is ignored)
+		MaxWidth          int       `yaml:"max_width"`           // Upper limit for auto_width in pixels; beyond it events are compressed (0 = no limit)
+		MinEventSpacing   int       `yaml:"min_event_spacing"`   // Horizontal room per event for auto_width in pixels (0 = timeline.min_text_spacing)
+		SparklineWidth    int       `yaml:"sparkline_width"`     // SVG width in pixels for timeline.mode "sparkline" (0 = 200)
+		SparklineHeight   int       `yaml:"sparkline_height"`    // SVG height in pixels for timeline.mode "sparkline" (0 = 30)
+		ShowIndex         bool      `yaml:"show_index"`          // List every event with its time in a column at the right edge; the timeline is narrowed to make room
+		IndexWidth        int       `yaml:"index_width"`         // Width in pixels reserved for show_index, added to margin_right (0 = 220)
+		TitleFont         TitleFont `yaml:"title_font"`          // Heading font for event titles and subtitles, independent of the body font
 	} `yaml:"layout"`
 	Timeline struct {
-		LineWidth          int  `yaml:"line_width"`           // Width of the main timeline line in pixels
-		ShowDates          bool `yaml:"show_dates"`           // Whether to display dates below/above event titles
-		ShowTimes          bool `yaml:"show_times"`           // Whether to show times along with dates when available
-		HorizontalBuffer   int  `yaml:"horizontal_buffer"`    // Horizontal buffer space before first and after last event in pixels
-		AvoidTextOverlap   bool `yaml:"avoid_text_overlap"`   // Enable collision avoidance for overlapping text
-		MinTextSpacing     int  `yaml:"min_text_spacing"`     // Minimum horizontal spacing in pixels to trigger overlap avoidance (lower values = more time-proportional)
-		MinCalloutLength   int  `yaml:"min_callout_length"`   // Minimum length of vertical callout lines in pixels
-		MaxCalloutLength   int  `yaml:"max_callout_length"`   // Maximum length of vertical callout lines in pixels
-		CalloutLevels      int  `yaml:"callout_levels"`       // Number of different callout levels for vertical text stacking (higher = more positioning options)
-		TextElementPadding int  `yaml:"text_element_padding"` // Vertical padding between text elements (title, timestamp, notes) in pixels
-		CalloutTextGap     int  `yaml:"callout_text_gap"`     // Gap between callout line endpoint and text start in pixels
+		LineWidth              int      `yaml:"line_width"`               // Width of the main timeline line in pixels
+		ShowDates              bool     `yaml:"show_dates"`               // Whether to display dates below/above event titles
+		ShowTimes              bool     `yaml:"show_times"`               // Whether to show times along with dates when available
+		HorizontalBuffer       int      `yaml:"horizontal_buffer"`        // Horizontal buffer space before first and after last event in pixels
+		HorizontalBufferLeft   *int     `yaml:"horizontal_buffer_left"`   // Buffer before the first event in pixels (unset = horizontal_buffer)
+		HorizontalBufferRight  *int     `yaml:"horizontal_buffer_right"`  // Buffer after the last event in pixels (unset = horizontal_buffer)
+		AvoidTextOverlap       bool     `yaml:"avoid_text_overlap"`       // Enable collision avoidance for overlapping text
+		MinTextSpacing         int      `yaml:"min_text_spacing"`         // Minimum horizontal spacing in pixels to trigger overlap avoidance (lower values = more time-proportional)
+		GuaranteedSpacing      int      `yaml:"guaranteed_spacing"`       // Hard minimum center-to-center distance in pixels between neighbouring events after the constraint solver, even within temporal clusters; events are spaced evenly when the width cannot fit it (0 = off)
+		MinCalloutLength       int      `yaml:"min_callout_length"`       // Minimum length of vertical callout lines in pixels
+		MaxCalloutLength       int      `yaml:"max_callout_length"`       // Maximum length of vertical callout lines in pixels
+		CalloutLevels          int      `yaml:"callout_levels"`           // Number of different callout levels for vertical text stacking (higher = more positioning options)
+		TextElementPadding     int      `yaml:"text_element_padding"`     // Vertical padding between text elements (title, timestamp, notes) in pixels
+		CalloutTextGap         int      `yaml:"callout_text_gap"`         // Gap between callout line endpoint and text start in pixels
+		CalloutTextGapAbove    *int     `yaml:"callout_text_gap_above"`   // Callout text gap for text above the timeline line (unset = callout_text_gap)
+		CalloutTextGapBelow    *int     `yaml:"callout_text_gap_below"`   // Callout text gap for text below the timeline line (unset = callout_text_gap)
+		DurationBars           bool     `yaml:"duration_bars"`            // Draw a bar along the timeline from each event's start to its end time
+		DurationBarHeight      int      `yaml:"duration_bar_height"`      // Height of duration bars in pixels (defaults to 10)
+		DurationBarMinWidth    int      `yaml:"duration_bar_min_width"`   // Minimum duration bar width in pixels; shorter bars are widened around the event start (0 = exact width)
+		DurationTextFit        string   `yaml:"duration_text_fit"`        // Title placement for duration bars: "auto" (inside if it fits), "inside", "beside", or "none"
+		FillGaps               bool     `yaml:"fill_gaps"`                // Draw a translucent bar along the timeline from each event to the next, showing how long each state lasted, in the event's category color (else colors.duration_bar)
+		SequenceConnectors     bool     `yaml:"sequence_connectors"`      // Draw faint vertical ticks from each marker down to a common sequence baseline
+		SequenceConnectorY     int      `yaml:"sequence_connector_y"`     // Y position of the sequence baseline in pixels (0 = top of the bottom margin)
+		EventTicks             bool     `yaml:"event_ticks"`              // Draw a short vertical tick centred on the line at each event, like a ruler, beneath the markers
+		EventTickLength        int      `yaml:"event_tick_length"`        // Total length of event ticks in pixels (defaults to 12)
+		FadeByAge              bool     `yaml:"fade_by_age"`              // Fade older events so recent events stand out
+		FadeReferenceTime      string   `yaml:"fade_reference_time"`      // Reference time for age fading in any supported timestamp format (empty = now)
+		FadeMinOpacity         float64  `yaml:"fade_min_opacity"`         // Opacity of the oldest event when fading by age (0-1, defaults to 0.3)
+		DrawOrder              string   `yaml:"draw_order"`               // Event draw order: "chronological" (default), "priority" (highest priority on top), or "reverse"
+		ShowLevelGuides        bool     `yaml:"show_level_guides"`        // Draw faint horizontal guide lines at each callout level above and below the timeline
+		OversizeText           string   `yaml:"oversize_text"`            // Handling of text wider than the usable canvas width: "wrap", "truncate", "shrink", or empty to leave it unchanged
+		ShowAxis               bool     `yaml:"show_axis"`                // Draw time axis ticks and labels along the timeline
+		AxisTickCount          int      `yaml:"axis_tick_count"`          // Approximate maximum number of axis ticks (defaults to 10)
+		AxisLabelMinGap        int      `yaml:"axis_label_min_gap"`       // Minimum pixel gap between neighbouring axis labels; crowded labels are skipped (first and last are always kept)
+		Reverse                bool     `yaml:"reverse"`                  // Reverse chronological layout: newest event on the left, oldest on the right
+		RangeStart             string   `yaml:"range_start"`              // Fixed start of the displayed time range in any supported timestamp format (empty = first event)
+		RangeEnd               string   `yaml:"range_end"`                // Fixed end of the displayed time range in any supported timestamp format (empty = last event)
+		OutOfRange             string   `yaml:"out_of_range"`             // Events outside the fixed range: "drop" (default), "clamp" to the range edge, or "edge" (drop and draw an off-screen indicator)
+		Mode                   string   `yaml:"mode"`                     // Render mode: "full" (default) or "sparkline" (only the baseline and a tick per event at layout.sparkline_width x layout.sparkline_height, for embedding in tables)
+		LayoutAlgorithm        string   `yaml:"layout_algorithm"`         // Event positioning: "cluster_optimized" (default), "time_proportional" (exact time positions), or "equal_spacing" (even spacing in chronological order with only 2D collision resolution)
+		Shape                  string   `yaml:"shape"`                    // Timeline baseline shape: "line" (default) or "arc" (a quadratic curve with markers placed along it)
+		AxisAnchor             string   `yaml:"axis_anchor"`              // Vertical position of the timeline line: "center" (default, events alternate sides), "top" (all events hang below), or "bottom" (all events rise above); one-sided layouts stack neighbours in callout levels, so a larger max_callout_length helps
+		ArcHeight              *int     `yaml:"arc_height"`               // Height of the arc apex above the straight baseline in pixels; negative values bend it downwards (unset = 60)
+		OptimizerBudget        int      `yaml:"optimizer_budget"`         // Number of callout height combinations the cluster optimizer tries per cluster: its built-in patterns are cut to this many or topped up with enumerated or sampled ones (0 = built-in patterns only)
+		CalloutLine            string   `yaml:"callout_line"`             // Callout connector style: "auto" (stepped above stepped_threshold), "stepped", or "straight"
+		CalloutLineNudge       *int     `yaml:"callout_line_nudge"`       // Sideways bend in pixels for callout lines that would coincide with another line on the same side; markers stay put (unset = 4, 0 = off)
+		SteppedThreshold       *int     `yaml:"stepped_threshold"`        // Pixels beyond min_callout_length at which auto callouts switch to stepped lines (unset = 10)
+		BaselineClearance      *int     `yaml:"baseline_clearance"`       // Minimum gap in pixels between event text and the timeline line; callouts lengthen to keep it (unset = min_callout_length minus the event's text height, never below 0)
+		CalloutStepRatio       *float64 `yaml:"callout_step_ratio"`       // Where stepped callout lines bend, as a fraction of the line from the marker to its end (0.0-1.0, unset = a third of the callout length)
+		SameTime               string   `yaml:"same_time"`                // Events sharing an exact timestamp: "spread" (default, spaced apart) or "stack" (same x with increasing callout lengths)
+		LabelPlacement         string   `yaml:"label_placement"`          // Event text placement relative to the callout: "center" (default), "left", or "right"
+		EmptyEvents            string   `yaml:"empty_events"`             // Events whose display columns are all empty: "keep" (default), "marker" (no callout), or "skip" (not drawn)
+		ShowClusters           bool     `yaml:"show_clusters"`            // Draw a bracket under each detected temporal cluster with its event count and time span (also set by --show-clusters)
+		DebugLayer             bool     `yaml:"debug_layer"`              // Overlay each event's index, ideal X, final X, and callout length next to its marker in a separate colored group (also set by --debug-svg)
+		CategoryStripes        bool     `yaml:"category_stripes"`         // Draw a short vertical rule in the category color left of each event's text block; needs columns.category_column and categories
+		ShowCount              bool     `yaml:"show_count"`               // Draw an "N events" label beside the end of the timeline line
+		CountPosition          string   `yaml:"count_position"`           // Corner for the event count label: "top-left" (default), "bottom-left", "top-right", or "bottom-right"
+		TimestampOnAxis        bool     `yaml:"timestamp_on_axis"`        // Draw each event's timestamp small beside its marker on the line instead of in the callout text block
+		HighlightWeekends      bool     `yaml:"highlight_weekends"`       // Shade Saturdays, Sundays, and timeline.holidays with background bands using colors.weekend
+		Holidays               []string `yaml:"holidays"`                 // Extra dates to shade like weekends when highlight_weekends is on (e.g., ["2024-12-25"])
+		ShowTimezone           bool     `yaml:"show_timezone"`            // Append the timestamp's UTC offset to displayed times: "UTC" for times without an offset, otherwise e.g. "+0200"
+		SmartDates             bool     `yaml:"smart_dates"`              // When every event falls on the same date, show only times on events and the date once as a header; multi-day timelines keep full dates
+		ClusterThreshold       string   `yaml:"cluster_threshold"`        // Time window for temporal clusters as a Go duration such as "30m" or "2h" (defaults to 2h); every run of events within it gets relaxed spacing
+		ShowProgress           bool     `yaml:"show_progress"`            // Draw the part of the timeline line before progress_time in colors.progress
+		DistinguishFuture      bool     `yaml:"distinguish_future"`       // Draw events after future_reference_time in the future_style, e.g. to separate a roadmap from history
+		FutureReferenceTime    string   `yaml:"future_reference_time"`    // Time after which events count as future for distinguish_future, in any supported timestamp format (empty = now)
+		FutureStyle            string   `yaml:"future_style"`             // How future events are distinguished: "dashed" (dashed marker border and callout line), "band" (colors.future background behind the future part of the timeline), or "both"
+		ProgressTime           string   `yaml:"progress_time"`            // Time that splits elapsed and future parts for show_progress, in any supported timestamp format (empty = now)
+		CalloutDirection       string   `yaml:"callout_direction"`        // Where the callout line ends relative to the text block: "auto" or "to-edge" (at the edge nearest the line) or "into-text" (past the text at its far edge, pointing back into it)
+		MergeDistance          int      `yaml:"merge_distance"`           // Merge events whose time-proportional markers are closer than this many pixels into one marker showing the count, with their titles listed in one callout (0 = off)
+		NumberEvents           bool     `yaml:"number_events"`            // Prefix each drawn event's title with its 1-based chronological number ("3. Title") for referencing events in accompanying text
+		GroupEvents            bool     `yaml:"group_events"`             // Wrap each event's callout, marker, and text in <g id="event-..."> with a stable ID for scripts and CSS
+		CollisionPreference    string   `yaml:"collision_preference"`     // How the 2D collision resolver used by layout_algorithm "equal_spacing" separates overlapping text: "auto" (default heuristics by time gap, distance, and side), "vertical" (always adjust callout heights), or "horizontal" (always move x positions)
+		CollisionVerticalLimit int      `yaml:"collision_vertical_limit"` // Callout length at which collision_preference "auto" or "vertical" switches a pair to horizontal separation once both callouts reach it (0 = max_callout_length)
+		MaxCollisionIterations int      `yaml:"max_collision_iterations"` // Iteration limit for the collision resolvers and constraint solver; higher values trade runtime for fewer remaining overlaps (0 = built-in limits of 10-20)
+		EdgeOverflow           string   `yaml:"edge_overflow"`            // Final pass for text that still extends past the canvas edges after collision resolution: "shift" (move or compress all positions inward), "shrink" (reduce the offending events' font sizes, down to half), or "" to leave it clipped
 	} `yaml:"timeline"`
 	Columns struct {
-		DisplayOrder       []string      `yaml:"display_order"`        // Simple format: ordered list of column names to display (e.g., ["title", "timestamp", "notes"])
-		DetailedColumns    []ColumnStyle `yaml:"detailed_columns"`     // Detailed format: full styling configuration per column (overrides simple format when UseDetailedStyling=true)
-		TimestampColumn    string        `yaml:"timestamp_column"`     // Name of the CSV column containing timestamp data (required, case-insensitive)
-		UseDetailedStyling bool          `yaml:"use_detailed_styling"` // Whether to use detailed column styling (true) or simple display order (false)
+		DisplayOrder       []string         `yaml:"display_order"`        // Simple format: ordered list of column names to display (e.g., ["title", "timestamp", "notes"])
+		DetailedColumns    []ColumnStyle    `yaml:"detailed_columns"`     // Detailed format: full styling configuration per column (overrides simple format when UseDetailedStyling=true)
+		TimestampColumn    ColumnCandidates `yaml:"timestamp_column"`     // Name of the CSV column containing timestamp data, or a list of candidate names where the first present in the header is used (required, case-insensitive)
+		EndTimestampColumn string           `yaml:"end_timestamp_column"` // Name of the CSV column containing end times for duration bars (optional, case-insensitive)
+		NumericTime        bool             `yaml:"numeric_time"`         // Read the timestamp and end timestamp columns as plain numbers (e.g. years ago) instead of dates, for ranges time.Time cannot hold; events are placed proportionally on the number axis, increasing to the right
+		NumericFormat      string           `yaml:"numeric_format"`       // fmt verb for numeric_time values shown as the event time, e.g. "%.2e" or "%.3g Ma" (defaults to "%g")
+		PriorityColumn     string           `yaml:"priority_column"`      // Name of the CSV column containing numeric event priorities used by timeline.draw_order (default "priority")
+		CalloutColumn      string           `yaml:"callout_column"`       // Name of the CSV column containing pinned callout lengths in pixels (optional; empty cells use the computed length)
+		HighlightColumn    string           `yaml:"highlight_column"`     // Name of the CSV column marking events to emphasize; any value except empty, "0", "false", or "no" highlights the event (optional)
+		ScaleColumn        string           `yaml:"scale_column"`         // Name of the CSV column holding a font size multiplier for all of an event's text, e.g. 1.5 (optional; empty or invalid cells use 1.0)
+		CategoryColumn     string           `yaml:"category_column"`      // Name of the CSV column holding each event's category, colored by the categories list (optional)
+		IconColumn         string           `yaml:"icon_column"`          // Name of the CSV column whose values pick an image from the icons list to draw instead of the event marker (optional)
+		ShapeColumn        string           `yaml:"shape_column"`         // Name of the CSV column holding each event's marker shape ("circle", "triangle", "square", or "diamond"), overriding event_marker.shape (optional; other values use event_marker.shape)
+		IDColumn           string           `yaml:"id_column"`            // Name of the CSV column whose values key the event group IDs for timeline.group_events (optional; empty uses the event index)
+		LazyQuotes         bool             `yaml:"lazy_quotes"`          // Tolerate stray quotes inside unquoted fields and unescaped quotes in quoted fields (also set by --lazy-quotes); only " is recognised as a quote character
+		UnescapeHTML       bool             `yaml:"unescape_html"`        // Decode HTML entities such as "&amp;" in CSV values so escapeXML does not escape them twice
+		Layout             string           `yaml:"layout"`               // Arrangement of an event's text: "stacked" (default, one element per row) or "grid" (pairs of elements side by side, e.g. label and value)
+		UseDetailedStyling bool             `yaml:"use_detailed_styling"` // Whether to use detailed column styling (true) or simple display order (false)
 	} `yaml:"columns"`
 	EventMarker struct {
 		Shape       string `yaml:"shape"`        // Marker shape: "circle", "triangle", "square", or "diamond"
@@ -149,7 +421,24 @@ type Config struct {
 		FillColor   string `yaml:"fill_color"`   // Fill color of the marker (hex color code, e.g., "#4285f4")
 		StrokeColor string `yaml:"stroke_color"` // Border/stroke color of the marker (hex color code)
 		StrokeWidth int    `yaml:"stroke_width"` // Width of the marker border in pixels
+		StrokeDash  string `yaml:"stroke_dash"`  // SVG stroke-dasharray for the marker border (e.g., "3,2"); empty for a solid border
+		Style       string `yaml:"style"`        // Marker fill style: "solid" (default), "ring" (hollow outline in the fill color), or "target" (ring with a centre dot)
+		Fill        string `yaml:"fill"`         // Set to "none" for outline-only markers that let overlapping markers show through (empty = use fill_color)
+		IconSize    int    `yaml:"icon_size"`    // Width and height in pixels of icons drawn for columns.icon_column (0 = twice size)
+		EmbedIcons  bool   `yaml:"embed_icons"`  // Embed icon image files in the SVG as base64 data URIs instead of linking to them; URLs are always linked
 	} `yaml:"event_marker"`
+	ShapeLegend []ShapeLegendEntry `yaml:"shape_legend"` // Marker shapes and their meanings, drawn as a key in the top-right corner (empty = no legend)
+	Groups      []GroupBracket     `yaml:"groups"`       // Labelled brackets drawn in the top margin over spans of time, to mark related events (empty = none)
+	Markers     []ReferenceMarker  `yaml:"markers"`      // Labelled vertical reference lines at fixed times, e.g. releases or freezes (empty = none)
+	Categories  []CategoryStyle    `yaml:"categories"`   // Colors for the values of columns.category_column, drawn by timeline.category_stripes (empty = none)
+	Icons       []EventIcon        `yaml:"icons"`        // Images for the values of columns.icon_column, drawn in place of the event marker (empty = none)
+
+	// BaseDir is the directory of the loaded config file, against which relative asset paths
+	// in it are resolved (see resolveConfigPath); empty for the default config
+	BaseDir string `yaml:"-"`
+	// OutputDir is the directory the SVG is written to, against which linked asset paths are
+	// made relative so the viewer finds them (see linkEventIcons); empty when unknown
+	OutputDir string `yaml:"-"`
 }
 
 // getDefaultConfig returns the default configuration with sensible defaults for all parameters.
@@ -165,78 +454,282 @@ type Config struct {
 func getDefaultConfig() Config {
 	return Config{
 		Font: struct {
-			Family string `yaml:"family"`
-			Size   int    `yaml:"size"`
+			Family string  `yaml:"family"`
+			Size   int     `yaml:"size"`
+			Scale  float64 `yaml:"scale"`
 		}{
 			Family: "Arial, sans-serif",
 			Size:   12,
+			Scale:  1.0,
 		},
 		Colors: struct {
-			Background string `yaml:"background"`
-			Timeline   string `yaml:"timeline"`
-			Events     string `yaml:"events"`
-			Text       string `yaml:"text"`
-			Notes      string `yaml:"notes"`
+			Background         string   `yaml:"background"`
+			Timeline           string   `yaml:"timeline"`
+			Events             string   `yaml:"events"`
+			Text               string   `yaml:"text"`
+			Notes              string   `yaml:"notes"`
+			DurationBar        string   `yaml:"duration_bar"`
+			EventTick          string   `yaml:"event_tick"`
+			Future             string   `yaml:"future"`
+			Progress           string   `yaml:"progress"`
+			Highlight          string   `yaml:"highlight"`
+			TimelineGradient   []string `yaml:"timeline_gradient"`
+			BackgroundGradient []string `yaml:"background_gradient"`
+			Weekend            string   `yaml:"weekend"`
+			AutoContrast       bool     `yaml:"auto_contrast"`
 		}{
-			Background: "#ffffff",
-			Timeline:   "#333333",
-			Events:     "#4285f4",
-			Text:       "#333333",
-			Notes:      "#666666",
+			Background:         "#ffffff",
+			Timeline:           "#333333",
+			Events:             "#4285f4",
+			Text:               "#333333",
+			Notes:              "#666666",
+			DurationBar:        "#a8c7fa",
+			EventTick:          "",
+			Future:             "#eef3fb",
+			Progress:           "#34a853",
+			Highlight:          "#e53935",
+			TimelineGradient:   nil,
+			BackgroundGradient: nil,
+			Weekend:            "#f0f0f0",
+			AutoContrast:       false,
 		},
 		Layout: struct {
-			Width        int `yaml:"width"`
-			Height       int `yaml:"height"`
-			MarginTop    int `yaml:"margin_top"`
-			MarginBottom int `yaml:"margin_bottom"`
-			MarginLeft   int `yaml:"margin_left"`
-			MarginRight  int `yaml:"margin_right"`
-			EventRadius  int `yaml:"event_radius"`
-			EventSpacing int `yaml:"event_spacing"`
+			Width             int       `yaml:"width"`
+			Height            int       `yaml:"height"`
+			MarginTop         int       `yaml:"margin_top"`
+			MarginBottom      int       `yaml:"margin_bottom"`
+			MarginLeft        int       `yaml:"margin_left"`
+			MarginRight       int       `yaml:"margin_right"`
+			EventRadius       int       `yaml:"event_radius"`
+			EventSpacing      int       `yaml:"event_spacing"`
+			Watermark         string    `yaml:"watermark"`
+			WatermarkOpacity  float64   `yaml:"watermark_opacity"`
+			WatermarkPosition string    `yaml:"watermark_position"`
+			TextReserveTop    int       `yaml:"text_reserve_top"`
+			TextReserveBottom int       `yaml:"text_reserve_bottom"`
+			AutoWidth         bool      `yaml:"auto_width"`
+			MaxWidth          int       `yaml:"max_width"`
+			MinEventSpacing   int       `yaml:"min_event_spacing"`
+			SparklineWidth    int       `yaml:"sparkline_width"`
+			SparklineHeight   int       `yaml:"sparkline_height"`
+			ShowIndex         bool      `yaml:"show_index"`
+			IndexWidth        int       `yaml:"index_width"`
+			TitleFont         TitleFont `yaml:"title_font"`
 		}{
-			Width:        1200,
-			Height:       800,
-			MarginTop:    50,
-			MarginBottom: 50,
-			MarginLeft:   100,
-			MarginRight:  100,
-			EventRadius:  8,
-			EventSpacing: 120,
+			Width:             1200,
+			Height:            800,
+			MarginTop:         50,
+			MarginBottom:      50,
+			MarginLeft:        100,
+			MarginRight:       100,
+			EventRadius:       8,
+			EventSpacing:      120,
+			Watermark:         "",
+			WatermarkOpacity:  0.1,
+			WatermarkPosition: "behind",
+			TextReserveTop:    0,
+			TextReserveBottom: 0,
+			AutoWidth:         false,
+			MaxWidth:          0,
+			MinEventSpacing:   0,
+			SparklineWidth:    200,
+			SparklineHeight:   30,
+			ShowIndex:         false,
+			IndexWidth:        220,
+			TitleFont:         TitleFont{},
 		},
 		Timeline: struct {
-			LineWidth          int  `yaml:"line_width"`
-			ShowDates          bool `yaml:"show_dates"`
-			ShowTimes          bool `yaml:"show_times"`
-			HorizontalBuffer   int  `yaml:"horizontal_buffer"`
-			AvoidTextOverlap   bool `yaml:"avoid_text_overlap"`
-			MinTextSpacing     int  `yaml:"min_text_spacing"`
-			MinCalloutLength   int  `yaml:"min_callout_length"`
-			MaxCalloutLength   int  `yaml:"max_callout_length"`
-			CalloutLevels      int  `yaml:"callout_levels"`
-			TextElementPadding int  `yaml:"text_element_padding"`
-			CalloutTextGap     int  `yaml:"callout_text_gap"`
+			LineWidth              int      `yaml:"line_width"`
+			ShowDates              bool     `yaml:"show_dates"`
+			ShowTimes              bool     `yaml:"show_times"`
+			HorizontalBuffer       int      `yaml:"horizontal_buffer"`
+			HorizontalBufferLeft   *int     `yaml:"horizontal_buffer_left"`
+			HorizontalBufferRight  *int     `yaml:"horizontal_buffer_right"`
+			AvoidTextOverlap       bool     `yaml:"avoid_text_overlap"`
+			MinTextSpacing         int      `yaml:"min_text_spacing"`
+			GuaranteedSpacing      int      `yaml:"guaranteed_spacing"`
+			MinCalloutLength       int      `yaml:"min_callout_length"`
+			MaxCalloutLength       int      `yaml:"max_callout_length"`
+			CalloutLevels          int      `yaml:"callout_levels"`
+			TextElementPadding     int      `yaml:"text_element_padding"`
+			CalloutTextGap         int      `yaml:"callout_text_gap"`
+			CalloutTextGapAbove    *int     `yaml:"callout_text_gap_above"`
+			CalloutTextGapBelow    *int     `yaml:"callout_text_gap_below"`
+			DurationBars           bool     `yaml:"duration_bars"`
+			DurationBarHeight      int      `yaml:"duration_bar_height"`
+			DurationBarMinWidth    int      `yaml:"duration_bar_min_width"`
+			DurationTextFit        string   `yaml:"duration_text_fit"`
+			FillGaps               bool     `yaml:"fill_gaps"`
+			SequenceConnectors     bool     `yaml:"sequence_connectors"`
+			SequenceConnectorY     int      `yaml:"sequence_connector_y"`
+			EventTicks             bool     `yaml:"event_ticks"`
+			EventTickLength        int      `yaml:"event_tick_length"`
+			FadeByAge              bool     `yaml:"fade_by_age"`
+			FadeReferenceTime      string   `yaml:"fade_reference_time"`
+			FadeMinOpacity         float64  `yaml:"fade_min_opacity"`
+			DrawOrder              string   `yaml:"draw_order"`
+			ShowLevelGuides        bool     `yaml:"show_level_guides"`
+			OversizeText           string   `yaml:"oversize_text"`
+			ShowAxis               bool     `yaml:"show_axis"`
+			AxisTickCount          int      `yaml:"axis_tick_count"`
+			AxisLabelMinGap        int      `yaml:"axis_label_min_gap"`
+			Reverse                bool     `yaml:"reverse"`
+			RangeStart             string   `yaml:"range_start"`
+			RangeEnd               string   `yaml:"range_end"`
+			OutOfRange             string   `yaml:"out_of_range"`
+			Mode                   string   `yaml:"mode"`
+			LayoutAlgorithm        string   `yaml:"layout_algorithm"`
+			Shape                  string   `yaml:"shape"`
+			AxisAnchor             string   `yaml:"axis_anchor"`
+			ArcHeight              *int     `yaml:"arc_height"`
+			OptimizerBudget        int      `yaml:"optimizer_budget"`
+			CalloutLine            string   `yaml:"callout_line"`
+			CalloutLineNudge       *int     `yaml:"callout_line_nudge"`
+			SteppedThreshold       *int     `yaml:"stepped_threshold"`
+			BaselineClearance      *int     `yaml:"baseline_clearance"`
+			CalloutStepRatio       *float64 `yaml:"callout_step_ratio"`
+			SameTime               string   `yaml:"same_time"`
+			LabelPlacement         string   `yaml:"label_placement"`
+			EmptyEvents            string   `yaml:"empty_events"`
+			ShowClusters           bool     `yaml:"show_clusters"`
+			DebugLayer             bool     `yaml:"debug_layer"`
+			CategoryStripes        bool     `yaml:"category_stripes"`
+			ShowCount              bool     `yaml:"show_count"`
+			CountPosition          string   `yaml:"count_position"`
+			TimestampOnAxis        bool     `yaml:"timestamp_on_axis"`
+			HighlightWeekends      bool     `yaml:"highlight_weekends"`
+			Holidays               []string `yaml:"holidays"`
+			ShowTimezone           bool     `yaml:"show_timezone"`
+			SmartDates             bool     `yaml:"smart_dates"`
+			ClusterThreshold       string   `yaml:"cluster_threshold"`
+			ShowProgress           bool     `yaml:"show_progress"`
+			DistinguishFuture      bool     `yaml:"distinguish_future"`
+			FutureReferenceTime    string   `yaml:"future_reference_time"`
+			FutureStyle            string   `yaml:"future_style"`
+			ProgressTime           string   `yaml:"progress_time"`
+			CalloutDirection       string   `yaml:"callout_direction"`
+			MergeDistance          int      `yaml:"merge_distance"`
+			NumberEvents           bool     `yaml:"number_events"`
+			GroupEvents            bool     `yaml:"group_events"`
+			CollisionPreference    string   `yaml:"collision_preference"`
+			CollisionVerticalLimit int      `yaml:"collision_vertical_limit"`
+			MaxCollisionIterations int      `yaml:"max_collision_iterations"`
+			EdgeOverflow           string   `yaml:"edge_overflow"`
 		}{
-			LineWidth:          2,
-			ShowDates:          true,
-			ShowTimes:          true,
-			HorizontalBuffer:   50,
-			AvoidTextOverlap:   true,
-			MinTextSpacing:     80,
-			MinCalloutLength:   60,
-			MaxCalloutLength:   180,
-			CalloutLevels:      4,
-			TextElementPadding: 2,
-			CalloutTextGap:     5, // 5-pixel gap between callout lines and text
+			LineWidth:              2,
+			ShowDates:              true,
+			ShowTimes:              true,
+			HorizontalBuffer:       50,
+			HorizontalBufferLeft:   nil,
+			HorizontalBufferRight:  nil,
+			AvoidTextOverlap:       true,
+			MinTextSpacing:         80,
+			GuaranteedSpacing:      0,
+			MinCalloutLength:       60,
+			MaxCalloutLength:       180,
+			CalloutLevels:          4,
+			TextElementPadding:     2,
+			CalloutTextGap:         5, // 5-pixel gap between callout lines and text
+			CalloutTextGapAbove:    nil,
+			CalloutTextGapBelow:    nil,
+			DurationBars:           false,
+			DurationBarHeight:      10,
+			DurationBarMinWidth:    0,
+			DurationTextFit:        "auto",
+			FillGaps:               false,
+			SequenceConnectors:     false,
+			SequenceConnectorY:     0,
+			EventTicks:             false,
+			EventTickLength:        12,
+			FadeByAge:              false,
+			FadeReferenceTime:      "",
+			FadeMinOpacity:         0.3,
+			DrawOrder:              "chronological",
+			ShowLevelGuides:        false,
+			OversizeText:           "",
+			ShowAxis:               false,
+			AxisTickCount:          10,
+			AxisLabelMinGap:        20,
+			Reverse:                false,
+			RangeStart:             "",
+			RangeEnd:               "",
+			OutOfRange:             "drop",
+			Mode:                   "full",
+			LayoutAlgorithm:        "cluster_optimized",
+			Shape:                  "line",
+			AxisAnchor:             "center",
+			ArcHeight:              nil,
+			OptimizerBudget:        0,
+			CalloutLine:            "auto",
+			CalloutLineNudge:       nil,
+			SteppedThreshold:       nil,
+			BaselineClearance:      nil,
+			CalloutStepRatio:       nil,
+			SameTime:               "spread",
+			LabelPlacement:         "center",
+			EmptyEvents:            "keep",
+			ShowClusters:           false,
+			DebugLayer:             false,
+			CategoryStripes:        false,
+			ShowCount:              false,
+			CountPosition:          "top-left",
+			TimestampOnAxis:        false,
+			HighlightWeekends:      false,
+			Holidays:               nil,
+			ShowTimezone:           false,
+			SmartDates:             false,
+			ClusterThreshold:       "2h",
+			ShowProgress:           false,
+			DistinguishFuture:      false,
+			FutureReferenceTime:    "",
+			FutureStyle:            "dashed",
+			ProgressTime:           "",
+			CalloutDirection:       "auto",
+			MergeDistance:          0,
+			NumberEvents:           false,
+			GroupEvents:            false,
+			CollisionPreference:    "auto",
+			CollisionVerticalLimit: 0,
+			MaxCollisionIterations: 0,
+			EdgeOverflow:           "",
 		},
 		Columns: struct {
-			DisplayOrder       []string      `yaml:"display_order"`
-			DetailedColumns    []ColumnStyle `yaml:"detailed_columns"`
-			TimestampColumn    string        `yaml:"timestamp_column"`
-			UseDetailedStyling bool          `yaml:"use_detailed_styling"`
+			DisplayOrder       []string         `yaml:"display_order"`
+			DetailedColumns    []ColumnStyle    `yaml:"detailed_columns"`
+			TimestampColumn    ColumnCandidates `yaml:"timestamp_column"`
+			EndTimestampColumn string           `yaml:"end_timestamp_column"`
+			NumericTime        bool             `yaml:"numeric_time"`
+			NumericFormat      string           `yaml:"numeric_format"`
+			PriorityColumn     string           `yaml:"priority_column"`
+			CalloutColumn      string           `yaml:"callout_column"`
+			HighlightColumn    string           `yaml:"highlight_column"`
+			ScaleColumn        string           `yaml:"scale_column"`
+			CategoryColumn     string           `yaml:"category_column"`
+			IconColumn         string           `yaml:"icon_column"`
+			ShapeColumn        string           `yaml:"shape_column"`
+			IDColumn           string           `yaml:"id_column"`
+			LazyQuotes         bool             `yaml:"lazy_quotes"`
+			UnescapeHTML       bool             `yaml:"unescape_html"`
+			Layout             string           `yaml:"layout"`
+			UseDetailedStyling bool             `yaml:"use_detailed_styling"`
 		}{
 			DisplayOrder:       []string{"title", TimestampColumn, "notes"}, // Default order
 			DetailedColumns:    []ColumnStyle{},                             // Empty by default
-			TimestampColumn:    TimestampColumn,                             // Default timestamp column name
+			TimestampColumn:    ColumnCandidates{TimestampColumn},           // Default timestamp column name
+			EndTimestampColumn: "",                                          // No duration data by default
+			NumericTime:        false,                                       // Time columns hold dates by default
+			NumericFormat:      "%g",                                        // Shortest exact form, switching to scientific notation for large exponents
+			PriorityColumn:     "priority",                                  // Default priority column name
+			CalloutColumn:      "",                                          // No pinned callout lengths by default
+			HighlightColumn:    "",                                          // No highlighted events by default
+			ScaleColumn:        "",                                          // All events use their configured font sizes by default
+			CategoryColumn:     "",                                          // Events have no category by default
+			IconColumn:         "",                                          // Events use the configured marker shape by default
+			ShapeColumn:        "",                                          // All events share event_marker.shape by default
+			IDColumn:           "",                                          // Group IDs use the event index by default
+			LazyQuotes:         false,                                       // Malformed quoting is a parse error by default
+			UnescapeHTML:       false,                                       // CSV values are used verbatim by default
+			Layout:             "stacked",                                   // One text element per row by default
 			UseDetailedStyling: false,                                       // Use simple format by default
 		},
 		EventMarker: struct {
@@ -245,13 +738,28 @@ func getDefaultConfig() Config {
 			FillColor   string `yaml:"fill_color"`
 			StrokeColor string `yaml:"stroke_color"`
 			StrokeWidth int    `yaml:"stroke_width"`
+			StrokeDash  string `yaml:"stroke_dash"`
+			Style       string `yaml:"style"`
+			Fill        string `yaml:"fill"`
+			IconSize    int    `yaml:"icon_size"`
+			EmbedIcons  bool   `yaml:"embed_icons"`
 		}{
 			Shape:       "circle",
 			Size:        8,
 			FillColor:   "#4285f4",
 			StrokeColor: "#333333",
 			StrokeWidth: 2,
+			StrokeDash:  "",
+			Style:       "solid",
+			Fill:        "",
+			IconSize:    0,
+			EmbedIcons:  false,
 		},
+		ShapeLegend: nil, // No shape legend by default
+		Groups:      nil, // No group brackets by default
+		Markers:     nil, // No reference lines by default
+		Categories:  nil, // No category colors by default
+		Icons:       nil, // No event icons by default
 	}
 }
 
@@ -264,6 +772,10 @@ func getDefaultConfig() Config {
 //   - Lower timeline.min_text_spacing (10-20) for more time-proportional positioning
 //   - Higher timeline.callout_levels (6-8) provides more positioning options for clustering
 //   - Set timeline.avoid_text_overlap=false to disable collision detection entirely
+//
+// The file format is chosen from the extension: ".json" and ".toml" files are accepted in
+// addition to YAML (".yaml", ".yml", or any other extension). All formats use the same
+// key names as the YAML configuration.
 func loadConfig(configPath string) (Config, error) {
 	if configPath == "" {
 		return getDefaultConfig(), nil
@@ -274,17 +786,329 @@ func loadConfig(configPath string) (Config, error) {
 		return Config{}, fmt.Errorf("error reading config file: %w", err)
 	}
 
+	data, err = convertConfigToYAML(data, filepath.Ext(configPath))
+	if err != nil {
+		return Config{}, fmt.Errorf("error parsing config file: %w", err)
+	}
+
 	var config Config
 	err = yaml.Unmarshal(data, &config)
 	if err != nil {
 		return Config{}, fmt.Errorf("error parsing config file: %w", err)
 	}
+	config.BaseDir = filepath.Dir(configPath)
 
 	return config, nil
 }
 
-// parseCSV reads and parses the CSV file containing timeline events with configurable columns
-func parseCSV(filename string, config Config) ([]TimelineEvent, error) {
+// resolveConfigPath returns an asset path from the config (e.g. an image to embed) resolved
+// against the config file's directory, so a config refers to files next to it regardless of
+// the working directory. Absolute paths, URLs, and paths in the default config are returned
+// unchanged.
+func resolveConfigPath(config Config, path string) string {
+	if path == "" || config.BaseDir == "" || filepath.IsAbs(path) || strings.Contains(path, "://") || strings.HasPrefix(strings.ToLower(path), "data:") {
+		return path
+	}
+	return filepath.Join(config.BaseDir, path)
+}
+
+// applyFontScale multiplies every configured font size by font.scale so that text, and the
+// bounding boxes estimated from it, grow without changing the rest of the layout. Unset
+// sizes are left at zero since they derive from font.size, which is already scaled.
+func applyFontScale(config *Config) {
+	scale := config.Font.Scale
+	if scale == 0 || scale == 1.0 {
+		return
+	}
+
+	scaleSize := func(size int) int {
+		if size <= 0 {
+			return size
+		}
+		return maxInt(int(math.Round(float64(size)*scale)), 1)
+	}
+	config.Font.Size = scaleSize(config.Font.Size)
+	config.Layout.TitleFont.Size = scaleSize(config.Layout.TitleFont.Size)
+	for i := range config.Columns.DetailedColumns {
+		config.Columns.DetailedColumns[i].FontSize = scaleSize(config.Columns.DetailedColumns[i].FontSize)
+	}
+	debugPrintf("Scaled font sizes by %.2f", scale)
+}
+
+// convertConfigToYAML converts JSON or TOML configuration data into YAML so that every
+// format is decoded through the yaml tags on Config. YAML data is returned unchanged.
+func convertConfigToYAML(data []byte, ext string) ([]byte, error) {
+	var values map[string]interface{}
+
+	switch strings.ToLower(ext) {
+	case ".json":
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("invalid TOML: %w", err)
+		}
+	default:
+		return data, nil
+	}
+
+	return yaml.Marshal(values)
+}
+
+// validateConfig checks configuration values that cannot be verified by YAML decoding alone
+func validateConfig(config Config) error {
+	timeSettings := []struct {
+		name  string
+		value string
+	}{
+		{"timeline.range_start", config.Timeline.RangeStart},
+		{"timeline.range_end", config.Timeline.RangeEnd},
+		{"timeline.fade_reference_time", config.Timeline.FadeReferenceTime},
+		{"timeline.progress_time", config.Timeline.ProgressTime},
+		{"timeline.future_reference_time", config.Timeline.FutureReferenceTime},
+	}
+	for _, setting := range timeSettings {
+		if strings.TrimSpace(setting.value) == "" {
+			continue
+		}
+		if _, err := parseTimestamp(strings.TrimSpace(setting.value)); err != nil {
+			return fmt.Errorf("invalid %s: %w", setting.name, err)
+		}
+	}
+
+	if threshold := strings.TrimSpace(config.Timeline.ClusterThreshold); threshold != "" {
+		if d, err := time.ParseDuration(threshold); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeline.cluster_threshold '%s': expected a positive duration such as 30m or 2h", threshold)
+		}
+	}
+
+	for _, holiday := range config.Timeline.Holidays {
+		if _, err := parseTimestamp(strings.TrimSpace(holiday)); err != nil {
+			return fmt.Errorf("invalid timeline.holidays entry '%s': %w", holiday, err)
+		}
+	}
+
+	rangeStart, hasStart := parseConfigTime(config.Timeline.RangeStart)
+	rangeEnd, hasEnd := parseConfigTime(config.Timeline.RangeEnd)
+	if hasStart && hasEnd && !rangeEnd.After(rangeStart) {
+		return fmt.Errorf("timeline.range_end must be after timeline.range_start")
+	}
+
+	if scale := config.Font.Scale; scale < 0 || math.IsNaN(scale) || math.IsInf(scale, 0) {
+		return fmt.Errorf("invalid font.scale %v: expected a positive multiplier", scale)
+	}
+
+	gradients := []struct {
+		name  string
+		stops []string
+	}{
+		{"colors.timeline_gradient", config.Colors.TimelineGradient},
+		{"colors.background_gradient", config.Colors.BackgroundGradient},
+	}
+	for _, gradient := range gradients {
+		if len(gradient.stops) != 0 && len(gradient.stops) != 2 {
+			return fmt.Errorf("invalid %s: expected two colors, got %d", gradient.name, len(gradient.stops))
+		}
+	}
+
+	for i, group := range config.Groups {
+		start, err := parseTimestamp(strings.TrimSpace(group.StartTime))
+		if err != nil {
+			return fmt.Errorf("invalid groups[%d].start_time: %w", i, err)
+		}
+		end, err := parseTimestamp(strings.TrimSpace(group.EndTime))
+		if err != nil {
+			return fmt.Errorf("invalid groups[%d].end_time: %w", i, err)
+		}
+		if end.Before(start) {
+			return fmt.Errorf("groups[%d].end_time must not be before start_time", i)
+		}
+	}
+
+	if config.Columns.NumericTime {
+		if formatted := formatNumericTime(1.5, config); strings.Contains(formatted, "%!") {
+			return fmt.Errorf("invalid columns.numeric_format '%s': expected one number verb such as %%g or %%.2e", config.Columns.NumericFormat)
+		}
+	}
+
+	if clearance := config.Timeline.BaselineClearance; clearance != nil && *clearance < 0 {
+		return fmt.Errorf("invalid timeline.baseline_clearance %d: must not be negative", *clearance)
+	}
+
+	if ratio := config.Timeline.CalloutStepRatio; ratio != nil && (*ratio < 0 || *ratio > 1 || math.IsNaN(*ratio)) {
+		return fmt.Errorf("invalid timeline.callout_step_ratio %v: expected a value from 0.0 to 1.0", *ratio)
+	}
+
+	for i, marker := range config.Markers {
+		if _, err := parseTimestamp(strings.TrimSpace(marker.Time)); err != nil {
+			return fmt.Errorf("invalid markers[%d].time: %w", i, err)
+		}
+		switch strings.ToLower(marker.Style) {
+		case "", "solid", "dashed", "dotted":
+		default:
+			return fmt.Errorf("invalid markers[%d].style '%s': expected solid, dashed, or dotted", i, marker.Style)
+		}
+	}
+
+	return nil
+}
+
+// configOverrides collects repeated --set flags in the order they were given
+type configOverrides []string
+
+// String returns the overrides as a comma-separated list for flag usage output
+func (o *configOverrides) String() string {
+	return strings.Join(*o, ",")
+}
+
+// Set records one "path=value" override; it implements flag.Value
+func (o *configOverrides) Set(value string) error {
+	*o = append(*o, value)
+	return nil
+}
+
+// eventFilters collects repeated --filter flags in the order they were given
+type eventFilters []string
+
+// String returns the filters as a comma-separated list for flag usage output
+func (f *eventFilters) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set records one "column=value" or "column!=value" filter; it implements flag.Value
+func (f *eventFilters) Set(value string) error {
+	if !strings.Contains(value, "=") {
+		return fmt.Errorf("expected column=value or column!=value")
+	}
+	*f = append(*f, value)
+	return nil
+}
+
+// applyConfigOverrides applies "section.field=value" assignments to the configuration.
+// Paths use the same names as the YAML configuration file (e.g., "timeline.min_text_spacing"
+// or "layout.width") and are matched case-insensitively. Values are converted to the type
+// of the target field: integers, floats, booleans, strings, and comma-separated string lists.
+func applyConfigOverrides(config *Config, overrides []string) error {
+	for _, override := range overrides {
+		path, value, found := strings.Cut(override, "=")
+		if !found {
+			return fmt.Errorf("invalid override '%s': expected path=value", override)
+		}
+		if err := setConfigValue(config, strings.TrimSpace(path), value); err != nil {
+			return fmt.Errorf("invalid override '%s': %w", override, err)
+		}
+		debugPrintf("Applied config override %s", override)
+	}
+	return nil
+}
+
+// setConfigValue walks a dotted YAML path through the Config struct and sets the final field
+func setConfigValue(config *Config, path, value string) error {
+	if path == "" {
+		return fmt.Errorf("empty config path")
+	}
+
+	field := reflect.ValueOf(config).Elem()
+	for _, part := range strings.Split(path, ".") {
+		if field.Kind() != reflect.Struct {
+			return fmt.Errorf("'%s' is not a config section", part)
+		}
+		next, ok := findFieldByYAMLName(field, part)
+		if !ok {
+			return fmt.Errorf("unknown config field '%s' in path '%s'", part, path)
+		}
+		field = next
+	}
+
+	// Optional values are pointers; setting one allocates it
+	if field.Kind() == reflect.Ptr && field.Type().Elem().Kind() != reflect.Struct {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("'%s' expects an integer, got '%s'", path, value)
+		}
+		field.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return fmt.Errorf("'%s' expects a number, got '%s'", path, value)
+		}
+		field.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("'%s' expects true or false, got '%s'", path, value)
+		}
+		field.SetBool(b)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("'%s' cannot be set from the command line", path)
+		}
+		items := []string{}
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("'%s' is a config section, not a value", path)
+	}
+
+	return nil
+}
+
+// findFieldByYAMLName returns the struct field whose yaml tag matches name (case-insensitive)
+func findFieldByYAMLName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if strings.EqualFold(tag, name) {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// newDecodingReader wraps r so that it yields UTF-8 text decoded from the named encoding.
+// Supported encodings:
+//   - "utf-8" (default): passes text through, stripping a leading byte order mark
+//   - "utf-16": uses the byte order mark to select endianness, little-endian if absent
+//   - "utf-16le", "utf-16be": fixed endianness, a matching byte order mark is stripped
+//   - "latin-1" (alias "iso-8859-1"): single-byte ISO 8859-1 text
+//   - "windows-1252" (alias "cp1252"): Windows Western European text
+func newDecodingReader(r io.Reader, encodingName string) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(encodingName)) {
+	case "", "utf-8", "utf8":
+		return transform.NewReader(r, unicode.UTF8BOM.NewDecoder()), nil
+	case "utf-16", "utf16":
+		return transform.NewReader(r, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewDecoder()), nil
+	case "utf-16le", "utf16le":
+		return transform.NewReader(r, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()), nil
+	case "utf-16be", "utf16be":
+		return transform.NewReader(r, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()), nil
+	case "latin-1", "latin1", "iso-8859-1":
+		return transform.NewReader(r, charmap.ISO8859_1.NewDecoder()), nil
+	case "windows-1252", "cp1252":
+		return transform.NewReader(r, charmap.Windows1252.NewDecoder()), nil
+	default:
+		return nil, fmt.Errorf("unsupported encoding '%s' (supported: utf-8, utf-16, utf-16le, utf-16be, latin-1, windows-1252)", encodingName)
+	}
+}
+
+// stdinCSV is the --csv value that reads the CSV data from standard input
+const stdinCSV = "-"
+
+// parseCSV opens the CSV file containing timeline events and parses it with parseCSVFrom
+func parseCSV(filename, encodingName string, config Config) ([]TimelineEvent, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, fmt.Errorf("error opening CSV file: %w", err)
@@ -295,7 +1119,23 @@ func parseCSV(filename string, config Config) ([]TimelineEvent, error) {
 		}
 	}()
 
-	reader := csv.NewReader(file)
+	return parseCSVFrom(file, encodingName, config)
+}
+
+// parseCSVFrom reads and parses CSV data containing timeline events with configurable columns.
+// The data is decoded from encodingName (see newDecodingReader) before CSV parsing.
+func parseCSVFrom(r io.Reader, encodingName string, config Config) ([]TimelineEvent, error) {
+	decoded, err := newDecodingReader(r, encodingName)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(decoded)
+	// Accept ragged rows; missing trailing fields are treated as empty
+	reader.FieldsPerRecord = -1
+	// encoding/csv only recognises '"' as the quote character; lazy quotes keep
+	// messy exports with stray quotes parseable instead of failing the whole file
+	reader.LazyQuotes = config.Columns.LazyQuotes
 	var events []TimelineEvent
 
 	// Read header to get column mapping
@@ -304,19 +1144,40 @@ func parseCSV(filename string, config Config) ([]TimelineEvent, error) {
 		return nil, fmt.Errorf("error reading CSV header: %w", err)
 	}
 
-	// Create case-insensitive column mapping
+	// Create case-insensitive column mapping. A byte order mark left on the first
+	// header (e.g. from Excel exports) would otherwise break column name matching.
 	columnMap := make(map[string]int)
 	for i, col := range header {
+		if i == 0 {
+			col = strings.TrimPrefix(col, "\ufeff")
+		}
 		columnMap[strings.ToLower(strings.TrimSpace(col))] = i
 	}
 
-	// Find the timestamp column
-	timestampColumnName := strings.ToLower(config.Columns.TimestampColumn)
-	timestampCol, exists := columnMap[timestampColumnName]
+	// Find the timestamp column: the first configured candidate present in the header
+	timestampCol, exists := -1, false
+	for _, candidate := range config.Columns.TimestampColumn {
+		if timestampCol, exists = columnMap[strings.ToLower(strings.TrimSpace(candidate))]; exists {
+			debugPrintf("Using timestamp column '%s'", candidate)
+			break
+		}
+	}
 	if !exists {
 		return nil, fmt.Errorf("timestamp column '%s' not found in CSV. Available columns: %v", config.Columns.TimestampColumn, header)
 	}
 
+	// Find the optional end timestamp column used for duration bars
+	endTimestampCol := -1
+	if config.Columns.EndTimestampColumn != "" {
+		endTimestampCol, exists = columnMap[strings.ToLower(config.Columns.EndTimestampColumn)]
+		if !exists {
+			return nil, fmt.Errorf("end timestamp column '%s' not found in CSV. Available columns: %v", config.Columns.EndTimestampColumn, header)
+		}
+	}
+
+	// Numeric end values, by event index, until mapNumericTimes converts them
+	numericEnds := make(map[int]float64)
+
 	// Read data rows
 	for {
 		record, err := reader.Read()
@@ -332,11 +1193,32 @@ func parseCSV(filename string, config Config) ([]TimelineEvent, error) {
 			return nil, fmt.Errorf("error parsing CSV row: %w", err)
 		}
 
+		if endTimestampCol >= 0 && endTimestampCol < len(record) {
+			endStr := strings.TrimSpace(record[endTimestampCol])
+			if endStr != "" && config.Columns.NumericTime {
+				end, err := parseNumericTime(endStr)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing CSV row: %w", err)
+				}
+				numericEnds[len(events)] = end
+			} else if endStr != "" {
+				event.EndTimestamp, err = parseTimestamp(endStr)
+				if err != nil {
+					return nil, fmt.Errorf("error parsing CSV row: %w", err)
+				}
+			}
+		}
+
 		events = append(events, event)
 	}
 
-	// Sort events by timestamp
-	sort.Slice(events, func(i, j int) bool {
+	if config.Columns.NumericTime {
+		mapNumericTimes(events, numericEnds)
+	}
+
+	// Sort events by timestamp; the stable sort keeps events with equal timestamps in row order
+	// so ties always get the same layout
+	sort.SliceStable(events, func(i, j int) bool {
 		return events[i].Timestamp.Before(events[j].Timestamp)
 	})
 
@@ -346,10 +1228,213 @@ func parseCSV(filename string, config Config) ([]TimelineEvent, error) {
 // parseCSVRowConfigurable parses a single CSV row into a TimelineEvent with configurable columns
 func parseCSVRowConfigurable(record []string, columnMap map[string]int, timestampCol int, config Config) (TimelineEvent, error) {
 	if timestampCol < 0 || timestampCol >= len(record) {
-		return TimelineEvent{}, fmt.Errorf("timestamp column index %d out of range", timestampCol)
+		return TimelineEvent{}, fmt.Errorf("row has %d fields and is missing the timestamp column", len(record))
+	}
+
+	// Numeric times get their Timestamp from mapNumericTimes once every row is read
+	var timestamp time.Time
+	var numericTime float64
+	var err error
+	if config.Columns.NumericTime {
+		numericTime, err = parseNumericTime(record[timestampCol])
+	} else {
+		timestamp, err = parseTimestamp(strings.TrimSpace(record[timestampCol]))
+	}
+	if err != nil {
+		return TimelineEvent{}, err
+	}
+
+	// Create data map for all columns; columns missing from short rows are empty
+	data := make(map[string]string)
+	for colName, colIndex := range columnMap {
+		if colIndex == timestampCol {
+			continue
+		}
+		if colIndex < len(record) {
+			value := strings.TrimSpace(record[colIndex])
+			if config.Columns.UnescapeHTML {
+				// Pre-escaped data is decoded here and escaped exactly once by escapeXML when drawn
+				value = html.UnescapeString(value)
+			}
+			data[colName] = value
+		} else {
+			data[colName] = ""
+		}
+	}
+
+	highlighted := config.Columns.HighlightColumn != "" && isTruthyValue(data[strings.ToLower(config.Columns.HighlightColumn)])
+
+	return TimelineEvent{
+		Timestamp:   timestamp,
+		Data:        data,
+		Highlighted: highlighted,
+		NumericTime: numericTime,
+	}, nil
+}
+
+// numericTimeEpoch and numericTimeSpan define the synthetic time axis that columns.numeric_time
+// values are mapped onto, so the rest of the pipeline can keep working with time.Time. A
+// century of nanoseconds resolves far finer than a pixel for any range of values.
+var numericTimeEpoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+const numericTimeSpan = 100 * 365 * 24 * time.Hour
+
+// parseNumericTime parses a columns.numeric_time value such as "4.5e9" or "13800000000"
+func parseNumericTime(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, fmt.Errorf("unable to parse numeric time '%s'", value)
+	}
+	return number, nil
+}
+
+// mapNumericTimes sets each event's Timestamp, and EndTimestamp for those with a numeric end in
+// ends (keyed by event index), to its value's proportional position between the smallest and
+// largest numeric value on the synthetic axis from numericTimeEpoch. Proportional layouts of
+// the mapped times match those of the numbers.
+func mapNumericTimes(events []TimelineEvent, ends map[int]float64) {
+	if len(events) == 0 {
+		return
+	}
+	lowest, highest := events[0].NumericTime, events[0].NumericTime
+	for _, event := range events {
+		lowest = math.Min(lowest, event.NumericTime)
+		highest = math.Max(highest, event.NumericTime)
+	}
+	for _, end := range ends {
+		lowest = math.Min(lowest, end)
+		highest = math.Max(highest, end)
+	}
+
+	toTime := func(value float64) time.Time {
+		if highest == lowest {
+			return numericTimeEpoch
+		}
+		return numericTimeEpoch.Add(time.Duration((value - lowest) / (highest - lowest) * float64(numericTimeSpan)))
+	}
+	for i := range events {
+		events[i].Timestamp = toTime(events[i].NumericTime)
+		if end, ok := ends[i]; ok {
+			events[i].EndTimestamp = toTime(end)
+		}
+	}
+	debugPrintf("Mapped numeric times %g to %g onto the synthetic time axis", lowest, highest)
+}
+
+// formatNumericTime formats a columns.numeric_time value with columns.numeric_format
+func formatNumericTime(value float64, config Config) string {
+	format := config.Columns.NumericFormat
+	if format == "" {
+		format = "%g"
+	}
+	return fmt.Sprintf(format, value)
+}
+
+// filterEvents keeps only the events matching every filter. A filter is "column=value"
+// (keep events whose column equals value) or "column!=value" (keep events whose column
+// differs from value). Column names and values are compared case-insensitively, and
+// surrounding whitespace is ignored. Filtering on a column the CSV does not have is an error.
+func filterEvents(events []TimelineEvent, filters []string) ([]TimelineEvent, error) {
+	type filter struct {
+		column, value string
+		negate        bool
+	}
+
+	parsed := make([]filter, 0, len(filters))
+	for _, raw := range filters {
+		column, value, _ := strings.Cut(raw, "=")
+		negate := strings.HasSuffix(column, "!")
+		column = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(column, "!")))
+		if column == "" {
+			return nil, fmt.Errorf("invalid filter '%s': missing column name", raw)
+		}
+		if len(events) > 0 {
+			if _, exists := events[0].Data[column]; !exists {
+				return nil, fmt.Errorf("invalid filter '%s': column '%s' not found in CSV", raw, column)
+			}
+		}
+		parsed = append(parsed, filter{column: column, value: strings.TrimSpace(value), negate: negate})
+	}
+
+	kept := make([]TimelineEvent, 0, len(events))
+	for _, event := range events {
+		matches := true
+		for _, f := range parsed {
+			if strings.EqualFold(strings.TrimSpace(event.Data[f.column]), f.value) == f.negate {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			kept = append(kept, event)
+		}
+	}
+	return kept, nil
+}
+
+// isTruthyValue reports whether a CSV flag value is set: anything except empty, "0", "false",
+// or "no" (case-insensitive) counts as true
+func isTruthyValue(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "no":
+		return false
+	}
+	return true
+}
+
+// highlightEvents marks the events at the comma-separated 0-based indices in list as
+// highlighted. Indices refer to the loaded events in chronological order.
+func highlightEvents(events []TimelineEvent, list string) error {
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		index, err := strconv.Atoi(item)
+		if err != nil || index < 0 || index >= len(events) {
+			return fmt.Errorf("invalid highlight index '%s': expected 0 to %d", item, len(events)-1)
+		}
+		events[index].Highlighted = true
+	}
+	return nil
+}
+
+// dedupeEvents removes events whose key columns match an earlier event, keeping the first
+// occurrence. Key columns are matched case-insensitively; the configured timestamp column
+// compares parsed times, so the same instant written in different formats is a duplicate.
+// It returns the remaining events and the number removed.
+func dedupeEvents(events []TimelineEvent, keyColumns []string, config Config) ([]TimelineEvent, int) {
+	seen := make(map[string]bool, len(events))
+	unique := make([]TimelineEvent, 0, len(events))
+	for _, event := range events {
+		parts := make([]string, 0, len(keyColumns))
+		for _, column := range keyColumns {
+			column = strings.ToLower(strings.TrimSpace(column))
+			if column == "" {
+				continue
+			}
+			if column == TimestampColumn || config.Columns.TimestampColumn.Contains(column) {
+				parts = append(parts, event.Timestamp.UTC().Format(time.RFC3339Nano))
+			} else {
+				parts = append(parts, event.Data[column])
+			}
+		}
+
+		key := strings.Join(parts, "\x00")
+		if seen[key] {
+			debugPrintf("Dropping duplicate event at %s", event.Timestamp.Format("2006-01-02 15:04"))
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, event)
 	}
 
-	// Parse timestamp
+	return unique, len(events) - len(unique)
+}
+
+// parseTimestamp parses a timestamp string by trying each supported format in turn
+func parseTimestamp(timestampStr string) (time.Time, error) {
 	timestampFormats := []string{
 		time.RFC3339,
 		"2006-01-02 15:04:05",
@@ -365,31 +1450,15 @@ func parseCSVRowConfigurable(record []string, columnMap map[string]int, timestam
 
 	var timestamp time.Time
 	var err error
-	timestampStr := strings.TrimSpace(record[timestampCol])
 
 	for _, format := range timestampFormats {
 		timestamp, err = time.Parse(format, timestampStr)
 		if err == nil {
-			break
-		}
-	}
-
-	if err != nil {
-		return TimelineEvent{}, fmt.Errorf("unable to parse timestamp '%s': %w", timestampStr, err)
-	}
-
-	// Create data map for all columns
-	data := make(map[string]string)
-	for colName, colIndex := range columnMap {
-		if colIndex < len(record) && colName != strings.ToLower(config.Columns.TimestampColumn) {
-			data[colName] = strings.TrimSpace(record[colIndex])
+			return timestamp, nil
 		}
 	}
 
-	return TimelineEvent{
-		Timestamp: timestamp,
-		Data:      data,
-	}, nil
+	return time.Time{}, fmt.Errorf("unable to parse timestamp '%s': %w", timestampStr, err)
 }
 
 // getColumnOrder returns the display order based on configuration format.
@@ -398,81 +1467,342 @@ func parseCSVRowConfigurable(record []string, columnMap map[string]int, timestam
 //   - Detailed mode: When columns.use_detailed_styling=true, extracts order from columns.detailed_columns
 //
 // The returned order determines the vertical stacking of text elements for each event.
+// With timeline.timestamp_on_axis the timestamp column is left out, since it is drawn on the line.
 func getColumnOrder(config Config) []string {
+	order := config.Columns.DisplayOrder
 	if config.Columns.UseDetailedStyling && len(config.Columns.DetailedColumns) > 0 {
-		order := make([]string, len(config.Columns.DetailedColumns))
+		order = make([]string, len(config.Columns.DetailedColumns))
 		for i, col := range config.Columns.DetailedColumns {
 			order[i] = col.Name
 		}
-		return order
 	}
-	return config.Columns.DisplayOrder
+
+	// The timestamp is drawn on the line by drawAxisTimestamp instead of in the text block
+	if config.Timeline.TimestampOnAxis {
+		filtered := make([]string, 0, len(order))
+		for _, elementName := range order {
+			if !strings.EqualFold(elementName, TimestampColumn) {
+				filtered = append(filtered, elementName)
+			}
+		}
+		return filtered
+	}
+	return order
 }
 
 // getColumnStyle returns the styling information for a column with intelligent defaults.
-// In detailed styling mode, returns the specific configuration from columns.detailed_columns.
-// In simple mode or when detailed config is missing, provides sensible fallbacks:
-//   - Uses global font.family and font.size as defaults
-//   - Applies appropriate colors based on column type (timestamp vs. other columns)
-//   - Generates CSS class names automatically
+// Precedence, highest first:
+//   - Fields set on the matching columns.detailed_columns entry (detailed styling mode only)
+//   - layout.title_font for titles and subtitles
+//   - Built-in column defaults (smaller notes-colored subtitles) over font.family, font.size,
+//     and colors.text, with CSS class names generated automatically
 //
+// A column without a detailed entry, including one only listed in display_order, or any
+// column when detailed_columns is empty, uses the simple styling unchanged.
 // Column names are matched case-insensitively for maximum compatibility.
 func getColumnStyle(columnName string, config Config) ColumnStyle {
 	columnName = strings.ToLower(columnName)
+	fallback := simpleColumnStyle(columnName, config)
 
-	if config.Columns.UseDetailedStyling {
-		for _, col := range config.Columns.DetailedColumns {
-			if strings.ToLower(col.Name) == columnName {
-				// Fill in defaults if not specified
-				style := col
-				if style.FontFamily == "" {
-					style.FontFamily = config.Font.Family
-				}
-				if style.FontSize == 0 {
-					style.FontSize = config.Font.Size
-				}
-				if style.FontWeight == "" {
-					style.FontWeight = "normal"
-				}
-				if style.Color == "" {
-					// Use default colors based on column type
-					switch columnName {
-					case "timestamp":
-						style.Color = config.Colors.Text
-					default:
-						style.Color = config.Colors.Text
-					}
-				}
-				if style.CSSClass == "" {
-					style.CSSClass = getElementClassName(columnName)
-				}
-				return style
-			}
+	if !config.Columns.UseDetailedStyling {
+		return fallback
+	}
+	for _, col := range config.Columns.DetailedColumns {
+		if strings.ToLower(col.Name) != columnName {
+			continue
+		}
+		// Fill fields the detailed entry leaves unset from the simple styling
+		style := col
+		if style.FontFamily == "" {
+			style.FontFamily = fallback.FontFamily
+		}
+		if style.FontSize == 0 {
+			style.FontSize = fallback.FontSize
+		}
+		if style.FontWeight == "" {
+			style.FontWeight = fallback.FontWeight
 		}
+		if style.Color == "" {
+			style.Color = fallback.Color
+		}
+		if style.CSSClass == "" {
+			style.CSSClass = fallback.CSSClass
+		}
+		if style.WrapWidth <= 0 {
+			style.WrapWidth = fallback.WrapWidth
+		}
+		return style
+	}
+	return fallback
+}
+
+// eventColumnStyle returns getColumnStyle for one event's text, with the font size multiplied
+// by the event's columns.scale_column value so drawing and collision bounds both use it
+func eventColumnStyle(event TimelineEvent, columnName string, config Config) ColumnStyle {
+	style := getColumnStyle(columnName, config)
+	scale := eventFontScale(event, config)
+	if event.TextScale > 0 {
+		scale *= event.TextScale
+	}
+	if scale != 1.0 {
+		style.FontSize = maxInt(int(math.Round(float64(style.FontSize)*scale)), 1)
+	}
+	return style
+}
+
+// eventFontScale returns the event's font size multiplier from columns.scale_column, or 1.0
+// when the column is unset or the cell is empty, not a number, or not positive
+func eventFontScale(event TimelineEvent, config Config) float64 {
+	if config.Columns.ScaleColumn == "" {
+		return 1.0
+	}
+	value := strings.TrimSpace(event.Data[strings.ToLower(config.Columns.ScaleColumn)])
+	if value == "" {
+		return 1.0
+	}
+	scale, err := strconv.ParseFloat(value, 64)
+	if err != nil || scale <= 0 || math.IsInf(scale, 0) {
+		debugPrintf("Ignoring invalid font scale '%s'", value)
+		return 1.0
 	}
+	return scale
+}
 
-	// Fallback to default styling
-	return ColumnStyle{
+// simpleColumnStyle returns the styling used outside detailed mode and for columns without
+// a columns.detailed_columns entry
+func simpleColumnStyle(columnName string, config Config) ColumnStyle {
+	style := ColumnStyle{
 		Name:       columnName,
 		FontFamily: config.Font.Family,
 		FontSize:   config.Font.Size,
 		FontWeight: "normal",
 		Color:      config.Colors.Text,
 		CSSClass:   getElementClassName(columnName),
+		WrapWidth:  DefaultWrapWidth,
+	}
+	if columnName == SubtitleColumn {
+		// Subtitles are a smaller, secondary label under the title
+		style.FontSize = maxInt(config.Font.Size-2, 6)
+		style.Color = config.Colors.Notes
+	}
+
+	// Explicit heading font settings apply to titles and subtitles
+	if columnName == "title" || columnName == SubtitleColumn {
+		titleFont := config.Layout.TitleFont
+		if titleFont.Family != "" {
+			style.FontFamily = titleFont.Family
+		}
+		if titleFont.Size > 0 {
+			style.FontSize = titleFont.Size
+			if columnName == SubtitleColumn {
+				style.FontSize = maxInt(titleFont.Size-2, 6)
+			}
+		}
+		if titleFont.Weight != "" {
+			style.FontWeight = titleFont.Weight
+		}
+		if titleFont.Color != "" {
+			style.Color = titleFont.Color
+		}
+	}
+	return style
+}
+
+// resolveTitleFont returns layout.title_font with empty fields filled from the derived
+// defaults: the global font family, font.size + 2, bold, and colors.text.
+func resolveTitleFont(config Config) TitleFont {
+	titleFont := config.Layout.TitleFont
+	if titleFont.Family == "" {
+		titleFont.Family = config.Font.Family
+	}
+	if titleFont.Size <= 0 {
+		titleFont.Size = config.Font.Size + 2
+	}
+	if titleFont.Weight == "" {
+		titleFont.Weight = "bold"
+	}
+	if titleFont.Color == "" {
+		titleFont.Color = config.Colors.Text
+	}
+	return titleFont
+}
+
+// wrapColumnText splits text into lines for columns that opt into wrapping via ColumnStyle.Wrap.
+// Text of other columns, or text that already fits within WrapWidth characters, stays on one line.
+// Cells containing the column's SplitOn delimiter put each value on its own line first.
+// With MaxLines set, only that many lines are kept and the last one ends with an ellipsis.
+func wrapColumnText(text string, style ColumnStyle) []string {
+	if style.SplitOn != "" && strings.Contains(text, style.SplitOn) {
+		// Each value of a multi-value cell starts its own line and wraps on its own
+		item := style
+		item.SplitOn = ""
+		item.MaxLines = 0
+		var lines []string
+		for _, value := range strings.Split(text, style.SplitOn) {
+			if value = strings.TrimSpace(value); value != "" {
+				lines = append(lines, wrapColumnText(value, item)...)
+			}
+		}
+		if len(lines) == 0 {
+			return []string{""}
+		}
+		return limitColumnLines(lines, style)
+	}
+
+	if !style.Wrap || style.WrapWidth <= 0 || len(text) <= style.WrapWidth {
+		return []string{text}
+	}
+	return limitColumnLines(wrapText(strings.Fields(text), style.WrapWidth), style)
+}
+
+// limitColumnLines cuts lines to the style's max_lines, ending the last kept line with "..."
+func limitColumnLines(lines []string, style ColumnStyle) []string {
+	if style.MaxLines > 0 && len(lines) > style.MaxLines {
+		lines = lines[:style.MaxLines]
+		last := []rune(lines[len(lines)-1])
+		if style.Wrap && style.WrapWidth > 0 && len(last) > style.WrapWidth-3 {
+			last = last[:maxInt(style.WrapWidth-3, 0)]
+		}
+		lines[len(lines)-1] = strings.TrimSpace(string(last)) + "..."
+	}
+	return lines
+}
+
+// wrappedExtraHeight returns the height that wrapped lines add below the first line of text.
+// It matches the tspan line height used by drawTextElement.
+func wrappedExtraHeight(lines []string, fontSize int) int {
+	if len(lines) <= 1 {
+		return 0
 	}
+	return (len(lines) - 1) * int(float64(fontSize)*1.2)
 }
 
 // getElementText returns the text for a display element
 func getElementText(event TimelineEvent, elementName string, config Config) string {
 	switch strings.ToLower(elementName) {
 	case "timestamp":
+		if config.Columns.NumericTime {
+			return formatNumericTime(event.NumericTime, config)
+		}
+		// The UTC offset is only shown alongside a time
+		zone := ""
+		if config.Timeline.ShowTimezone {
+			zone = " " + utcOffsetLabel(event.Timestamp)
+		}
+		if config.Timeline.SmartDates {
+			// The shared date is drawn once in the header (see drawDateHeader)
+			return event.Timestamp.Format("15:04") + zone
+		}
 		if config.Timeline.ShowTimes && (event.Timestamp.Hour() != 0 || event.Timestamp.Minute() != 0 || event.Timestamp.Second() != 0) {
-			return event.Timestamp.Format("2006-01-02 15:04")
+			return event.Timestamp.Format("2006-01-02 15:04") + zone
 		}
 		return event.Timestamp.Format("2006-01-02")
 	default:
-		return event.Data[strings.ToLower(elementName)]
+		text := event.Data[strings.ToLower(elementName)]
+		if format := getColumnStyle(elementName, config).NumberFormat; format != "" {
+			text = formatNumber(text, format)
+		}
+		if event.Number > 0 && strings.EqualFold(elementName, "title") {
+			// Untitled events still show their number so the sequence has no gaps
+			return strings.TrimSpace(fmt.Sprintf("%d. %s", event.Number, text))
+		}
+		return text
+	}
+}
+
+// formatNumber applies a ColumnStyle number_format to a numeric string.
+// Values that do not parse as numbers, and unknown formats, are returned unchanged.
+//   - "grouped": thousands separators, keeping any decimal part (1234567.5 -> 1,234,567.5);
+//     only plain decimals are grouped, so "Inf", "NaN", hex, and exponent forms stay unchanged
+//   - "0.0k" (alias "compact"): one decimal with a k/M/B/T suffix (1234567 -> 1.2M)
+//   - "bytes": binary byte units with one decimal (1536 -> 1.5 KB)
+func formatNumber(text, format string) string {
+	trimmed := strings.TrimSpace(text)
+	value, err := strconv.ParseFloat(trimmed, 64)
+	if err != nil {
+		return text
+	}
+
+	switch strings.ToLower(format) {
+	case "grouped":
+		if !isPlainDecimal(trimmed) {
+			return text
+		}
+		sign := ""
+		if strings.HasPrefix(trimmed, "-") || strings.HasPrefix(trimmed, "+") {
+			sign, trimmed = trimmed[:1], trimmed[1:]
+			if sign == "+" {
+				sign = ""
+			}
+		}
+		intPart, fracPart, hasFrac := strings.Cut(trimmed, ".")
+		var grouped strings.Builder
+		for i, digit := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				grouped.WriteByte(',')
+			}
+			grouped.WriteRune(digit)
+		}
+		if hasFrac {
+			return sign + grouped.String() + "." + fracPart
+		}
+		return sign + grouped.String()
+	case "0.0k", "compact":
+		suffixes := []string{"", "k", "M", "B", "T"}
+		magnitude := 0
+		for absFloat(value) >= 1000 && magnitude < len(suffixes)-1 {
+			value /= 1000
+			magnitude++
+		}
+		if magnitude == 0 {
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+		return strconv.FormatFloat(value, 'f', 1, 64) + suffixes[magnitude]
+	case "bytes":
+		units := []string{"B", "KB", "MB", "GB", "TB", "PB"}
+		unit := 0
+		for absFloat(value) >= 1024 && unit < len(units)-1 {
+			value /= 1024
+			unit++
+		}
+		if unit == 0 {
+			return strconv.FormatFloat(value, 'f', -1, 64) + " B"
+		}
+		return strconv.FormatFloat(value, 'f', 1, 64) + " " + units[unit]
+	default:
+		return text
+	}
+}
+
+// isPlainDecimal reports whether text is an optionally signed run of digits with an optional
+// fractional part, such as "-1234.5"
+func isPlainDecimal(text string) bool {
+	if strings.HasPrefix(text, "-") || strings.HasPrefix(text, "+") {
+		text = text[1:]
+	}
+	intPart, fracPart, hasFrac := strings.Cut(text, ".")
+	isDigits := func(part string) bool {
+		if part == "" {
+			return false
+		}
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return false
+			}
+		}
+		return true
 	}
+	return isDigits(intPart) && (!hasFrac || isDigits(fracPart))
+}
+
+// utcOffsetLabel returns "UTC" for a time without an offset and the numeric offset, such as
+// "+0200", otherwise. Timestamps only carry an offset, not a zone name, so the label is built
+// from the offset rather than the zone abbreviation, which would depend on the local zone.
+func utcOffsetLabel(t time.Time) string {
+	if _, offset := t.Zone(); offset != 0 {
+		return t.Format("-0700")
+	}
+	return "UTC"
 }
 
 // getElementClassName returns the CSS class for a display element
@@ -487,93 +1817,454 @@ func getElementClassName(elementName string) string {
 	}
 }
 
-// calculateConfigurableTextPositions calculates positions for all display elements
+// calculateConfigurableTextPositions calculates positions for all display elements.
+// Elements are stacked in the rows from textRows; both elements of a grid row share a baseline.
+// The first row is always the one nearest the timeline and later rows stack away from it:
+// downwards when above is true, which places the text below the line, and upwards otherwise,
+// where eventY is the lowest baseline and the text sits above the line. Both sides therefore
+// read outwards from the line in display_order.
 func calculateConfigurableTextPositions(event TimelineEvent, eventY int, above bool, config Config) map[string]int {
 	positions := make(map[string]int)
-	columnOrder := getColumnOrder(config)
 	padding := config.Timeline.TextElementPadding
 
 	currentY := eventY
+	placed := false
+	previousHeight, previousExtra := 0, 0
 
-	for i, elementName := range columnOrder {
-		text := getElementText(event, elementName, config)
-		if text != "" {
-			style := getColumnStyle(elementName, config)
-			bounds := estimateTextBounds(text, style.FontSize)
+	for _, row := range textRows(event, config) {
+		height, extra := textRowSize(event, row, config)
 
-			if i == 0 {
-				// First element positioning
-				positions[elementName] = currentY
-			} else {
-				// Subsequent elements are offset by text height + padding
-				if above {
-					currentY += bounds.Height + padding
-				} else {
-					currentY -= bounds.Height + padding
-				}
-				positions[elementName] = currentY
+		if !placed {
+			// First displayed row positioning; empty columns before it take no space
+			if !above {
+				currentY -= extra
 			}
+			placed = true
+		} else if above {
+			// Stacking downwards: clear the previous row's wrapped lines, then this
+			// row's own height, since text extends upwards from its baseline
+			currentY += previousExtra + height + padding
+		} else {
+			// Stacking upwards: clear the previous row's height, then lift this
+			// row by its wrapped lines so they end above the previous row
+			currentY -= previousHeight + padding + extra
+		}
+		for _, elementName := range row {
+			positions[elementName] = currentY
 		}
+		previousHeight, previousExtra = height, extra
 	}
 
 	return positions
-} // generateSVG creates an SVG timeline from the events and config
+}
+
+// textRows returns the event's displayed elements grouped into the rows they are drawn in:
+// one element per row for columns.layout "stacked", or pairs side by side for "grid", where
+// a final odd element gets a row of its own. Columns without text for the event are skipped.
+func textRows(event TimelineEvent, config Config) [][]string {
+	perRow := 1
+	if gridTextLayout(config) {
+		perRow = 2
+	}
+
+	var rows [][]string
+	for _, elementName := range getColumnOrder(config) {
+		if getElementText(event, elementName, config) == "" {
+			continue
+		}
+		if len(rows) == 0 || len(rows[len(rows)-1]) == perRow {
+			rows = append(rows, nil)
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], elementName)
+	}
+	return rows
+}
+
+// textRowSize returns the height of a row's tallest element and the largest extra height
+// of its wrapped lines
+func textRowSize(event TimelineEvent, row []string, config Config) (height, extra int) {
+	for _, elementName := range row {
+		text := getElementText(event, elementName, config)
+		lines, fontSize, _ := fitOversizeText(text, eventColumnStyle(event, elementName, config), config)
+		height = maxInt(height, estimateTextBounds(text, fontSize).Height)
+		extra = maxInt(extra, wrappedExtraHeight(lines, fontSize))
+	}
+	return height, extra
+}
+
+// gridTextLayout reports whether columns.layout arranges event text in a two-column grid
+func gridTextLayout(config Config) bool {
+	return strings.EqualFold(config.Columns.Layout, "grid")
+}
+
+// elementTextWidth estimates the drawn width of an element; wrapped columns are at most
+// wrap_width characters wide, split multi-value cells as wide as their widest line, and text
+// changed by timeline.oversize_text as wide as its widest fitted line
+func elementTextWidth(text string, style ColumnStyle, config Config) int {
+	if lines, fontSize, fitted := fitOversizeText(text, style, config); fitted {
+		width := 0
+		for _, line := range lines {
+			width = maxInt(width, estimateTextWidth(line, fontSize))
+		}
+		return width
+	}
+
+	width := estimateTextWidth(text, style.FontSize)
+	if lines := wrapColumnText(text, style); style.SplitOn != "" && len(lines) > 1 {
+		width = 0
+		for _, line := range lines {
+			width = maxInt(width, estimateTextWidth(line, style.FontSize))
+		}
+	} else if len(lines) > 1 {
+		width = minInt(estimateTextWidth(strings.Repeat("A", style.WrapWidth), style.FontSize), width)
+	}
+	return width
+}
+
+// textBlockWidth returns the width of an event's text block: its widest element, or for grid
+// layouts both columns and the gap between them
+func textBlockWidth(event TimelineEvent, config Config) int {
+	if gridTextLayout(config) {
+		return gridTextWidth(event, config)
+	}
+	width := 0
+	for _, elementName := range getColumnOrder(config) {
+		if text := getElementText(event, elementName, config); text != "" {
+			width = maxInt(width, elementTextWidth(text, eventColumnStyle(event, elementName, config), config))
+		}
+	}
+	return width
+}
+
+// gridColumnGap is the horizontal space between the two columns of a grid text layout
+const gridColumnGap = 8
+
+// gridColumnWidths returns the widths of the left and right columns of an event's grid text.
+// Odd final elements span the whole grid, so they widen it only when wider than both columns.
+func gridColumnWidths(event TimelineEvent, config Config) (left, right int) {
+	spanning := 0
+	for _, row := range textRows(event, config) {
+		for i, elementName := range row {
+			width := elementTextWidth(getElementText(event, elementName, config), eventColumnStyle(event, elementName, config), config)
+			switch {
+			case len(row) == 1:
+				spanning = maxInt(spanning, width)
+			case i == 0:
+				left = maxInt(left, width)
+			default:
+				right = maxInt(right, width)
+			}
+		}
+	}
+	if total := left + gridColumnGap + right; spanning > total {
+		left += (spanning - total) / 2
+		right = spanning - gridColumnGap - left
+	}
+	return left, right
+}
+
+// gridTextWidth returns the footprint width of an event's grid text
+func gridTextWidth(event TimelineEvent, config Config) int {
+	left, right := gridColumnWidths(event, config)
+	return left + gridColumnGap + right
+}
+
+// textAnchorPoint is where one text element is drawn horizontally and how it is anchored there
+type textAnchorPoint struct {
+	X      int
+	Anchor string
+}
+
+// gridTextAnchors returns the anchor point of each element for columns.layout "grid", with the
+// grid placed like a single text block by timeline.label_placement. Left column elements are
+// right-aligned against the gap and right column elements left-aligned after it, so labels
+// and values line up; single elements on the last row are centred. Returns nil when the
+// layout is stacked.
+func gridTextAnchors(event TimelineEvent, x int, config Config) map[string]textAnchorPoint {
+	if !gridTextLayout(config) {
+		return nil
+	}
+
+	left, right := gridColumnWidths(event, config)
+	width := left + gridColumnGap + right
+	anchorX, _ := labelAnchor(x, config)
+	blockLeft := labelLeftEdge(anchorX, width, config)
+
+	anchors := make(map[string]textAnchorPoint)
+	for _, row := range textRows(event, config) {
+		if len(row) == 1 {
+			anchors[row[0]] = textAnchorPoint{X: blockLeft + width/2, Anchor: "middle"}
+			continue
+		}
+		anchors[row[0]] = textAnchorPoint{X: blockLeft + left, Anchor: "end"}
+		anchors[row[1]] = textAnchorPoint{X: blockLeft + left + gridColumnGap, Anchor: "start"}
+	}
+	return anchors
+}
+
+// generateSVG creates an SVG timeline from the events and config
 func generateSVG(events []TimelineEvent, config Config) string {
-	if len(events) == 0 {
+	var svg strings.Builder
+	if err := GenerateSVGTo(&svg, events, config); err != nil {
+		debugPrintf("SVG generation failed: %v", err)
 		return ""
 	}
+	return svg.String()
+}
+
+// svgWriter is the destination the drawing helpers write SVG markup to.
+// Both *strings.Builder and *bufio.Writer satisfy it.
+type svgWriter interface {
+	io.Writer
+	io.StringWriter
+}
+
+// GenerateSVGTo renders the timeline and streams the SVG markup to w as each element is drawn,
+// so large timelines never need to be held in memory as a single string. Output is buffered
+// and flushed before returning; the first write error, if any, is returned.
+func GenerateSVGTo(w io.Writer, events []TimelineEvent, config Config) error {
+	if config.Columns.NumericTime {
+		config = withoutCalendarFeatures(config)
+	}
+
+	// Restrict events to the configured fixed time range, if any
+	events, hiddenBefore, hiddenAfter := applyTimeRange(events, config)
+	if strings.EqualFold(config.Timeline.EmptyEvents, "skip") {
+		events = skipEmptyEvents(events, config)
+	}
+	if len(events) == 0 {
+		return fmt.Errorf("no events to render")
+	}
+	rangeStart, rangeEnd := getTimeRange(events, config)
+
+	// Sparklines skip layout, text, and callouts entirely
+	if strings.EqualFold(config.Timeline.Mode, "sparkline") {
+		return generateSparklineTo(w, events, rangeStart, rangeEnd, config)
+	}
+
+	// Reserve the index column at the right edge; the timeline is narrowed to fit beside it
+	if config.Layout.ShowIndex {
+		config.Layout.MarginRight += eventIndexWidth(config)
+	}
+
+	if config.Layout.AutoWidth {
+		config.Layout.Width = calculateAutoWidth(len(events), config)
+	}
+	if err := validateLayoutDimensions(config); err != nil {
+		return err
+	}
+	if config.Columns.IconColumn != "" {
+		if config.EventMarker.EmbedIcons {
+			icons, err := embedEventIcons(config)
+			if err != nil {
+				return err
+			}
+			config.Icons = icons
+		} else {
+			config.Icons = linkEventIcons(config)
+		}
+	}
+	if globalManifest != nil {
+		globalManifest.Width, globalManifest.Height = config.Layout.Width, config.Layout.Height
+	}
+
+	// Smart dates only apply when the whole timeline falls on a single date
+	if config.Timeline.SmartDates && !sameDate(events) {
+		debugPrintf("Smart dates: events span multiple dates, keeping full dates")
+		config.Timeline.SmartDates = false
+	}
+
+	// Pick readable colors for the background; explicit detailed column colors still win
+	if config.Colors.AutoContrast {
+		textColor := contrastingTextColor(config.Colors.Background)
+		debugPrintf("Auto contrast: using %s text, lines, and markers on background %s", textColor, config.Colors.Background)
+		config.Colors.Text = textColor
+		config.Colors.Notes = textColor
+		config.Colors.Timeline = textColor
+		config.Colors.Events = textColor
+		if config.Colors.EventTick != "" {
+			config.Colors.EventTick = textColor
+		}
+		config.EventMarker.StrokeColor = textColor
+		if config.EventMarker.FillColor != "" {
+			config.EventMarker.FillColor = textColor
+		}
+	}
 
 	// Calculate timeline dimensions
 	timelineWidth := config.Layout.Width - config.Layout.MarginLeft - config.Layout.MarginRight
-	timelineHeight := config.Layout.Height - config.Layout.MarginTop - config.Layout.MarginBottom
 
 	// Calculate usable timeline width after accounting for horizontal buffers
-	usableTimelineWidth := timelineWidth - (2 * config.Timeline.HorizontalBuffer)
-	timelineStartX := config.Layout.MarginLeft + config.Timeline.HorizontalBuffer
+	bufferLeft, bufferRight := horizontalBuffers(config)
+	usableTimelineWidth := timelineWidth - bufferLeft - bufferRight
+	timelineStartX := config.Layout.MarginLeft + bufferLeft
+
+	// Collapse events whose markers would nearly coincide into one counted marker
+	if config.Timeline.MergeDistance > 0 {
+		events = mergeDenseEvents(events, rangeStart, rangeEnd, timelineStartX, usableTimelineWidth, config)
+		rangeStart, rangeEnd = getTimeRange(events, config)
+	}
 
-	// Start building SVG
-	var svg strings.Builder
+	// Gradients replace the solid background and line colors when configured
+	backgroundFill, timelineStroke := config.Colors.Background, config.Colors.Timeline
+	if len(config.Colors.BackgroundGradient) == 2 {
+		backgroundFill = "url(#background-gradient)"
+	}
+	if len(config.Colors.TimelineGradient) == 2 {
+		timelineStroke = "url(#timeline-gradient)"
+	}
+
+	// Start writing SVG
+	titleFont := resolveTitleFont(config)
+	svg := bufio.NewWriter(w)
 	svg.WriteString(fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
 <svg width="%d" height="%d" xmlns="http://www.w3.org/2000/svg">
 <rect width="100%%" height="100%%" fill="%s"/>
 <defs>
-<style>
-.title-text { font-family: %s; font-size: %dpx; font-weight: bold; fill: %s; }
+%s<style>
+.title-text { font-family: %s; font-size: %dpx; font-weight: %s; fill: %s; }
 .notes-text { font-family: %s; font-size: %dpx; fill: %s; }
 .date-text { font-family: %s; font-size: %dpx; fill: %s; }
 </style>
 </defs>
-`, config.Layout.Width, config.Layout.Height, config.Colors.Background,
-		config.Font.Family, config.Font.Size+2, config.Colors.Text,
+`, config.Layout.Width, config.Layout.Height, backgroundFill, gradientDefs(timelineWidth, config),
+		titleFont.Family, titleFont.Size, titleFont.Weight, titleFont.Color,
 		config.Font.Family, config.Font.Size-2, config.Colors.Notes,
 		config.Font.Family, config.Font.Size-1, config.Colors.Text))
 
-	// Draw main timeline line
-	timelineY := config.Layout.MarginTop + timelineHeight/2
-	svg.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d"/>`,
-		config.Layout.MarginLeft, timelineY,
-		config.Layout.MarginLeft+timelineWidth, timelineY,
-		config.Colors.Timeline, config.Timeline.LineWidth))
+	// Draw the watermark before the content unless it is configured to sit on top
+	if config.Layout.Watermark != "" && !strings.EqualFold(config.Layout.WatermarkPosition, "above") {
+		drawWatermark(svg, config)
+	}
+
+	// Shade weekends and holidays behind everything; equal spacing has no time scale to map them onto
+	if config.Timeline.HighlightWeekends && !strings.EqualFold(config.Timeline.LayoutAlgorithm, "equal_spacing") {
+		drawWeekendShading(svg, rangeStart, rangeEnd, timelineStartX, usableTimelineWidth, config)
+	}
+
+	// Shade the future part of the timeline behind everything drawn on it
+	if config.Timeline.DistinguishFuture && futureStyleIncludes(config, "band") && !strings.EqualFold(config.Timeline.LayoutAlgorithm, "equal_spacing") {
+		drawFutureBand(svg, rangeStart, rangeEnd, timelineStartX, usableTimelineWidth, config)
+	}
+
+	// Draw main timeline line, or a quadratic curve whose apex sits arc_height above the baseline
+	timelineY := timelineAxisY(config)
+	recordManifest(ManifestElement{Type: "timeline", X: config.Layout.MarginLeft, Y: timelineY, X2: config.Layout.MarginLeft + timelineWidth, Y2: timelineY})
+	if strings.EqualFold(config.Timeline.Shape, "arc") {
+		svg.WriteString(fmt.Sprintf(`<path d="M%d,%d Q%d,%d %d,%d" stroke="%s" stroke-width="%d" fill="none"/>`,
+			config.Layout.MarginLeft, timelineY,
+			config.Layout.MarginLeft+timelineWidth/2, timelineY-2*arcHeight(config),
+			config.Layout.MarginLeft+timelineWidth, timelineY,
+			timelineStroke, config.Timeline.LineWidth))
+	} else {
+		svg.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d"/>`,
+			config.Layout.MarginLeft, timelineY,
+			config.Layout.MarginLeft+timelineWidth, timelineY,
+			timelineStroke, config.Timeline.LineWidth))
+	}
+
+	// Recolor the elapsed part of the line; equal spacing has no time scale to split it on
+	if config.Timeline.ShowProgress && !strings.EqualFold(config.Timeline.LayoutAlgorithm, "equal_spacing") {
+		drawProgressOverlay(svg, rangeStart, rangeEnd, timelineY, timelineStartX, usableTimelineWidth, config)
+	}
+
+	// Indicate events hidden outside the fixed time range
+	if hiddenBefore > 0 || hiddenAfter > 0 {
+		drawOutOfRangeIndicators(svg, hiddenBefore, hiddenAfter, timelineY, config.Layout.MarginLeft, config.Layout.MarginLeft+timelineWidth, config)
+	}
+
+	// Show the shared date once instead of on every event
+	if config.Timeline.SmartDates {
+		drawDateHeader(svg, events[0].Timestamp, config)
+	}
+
+	// Label the number of events actually drawn
+	if config.Timeline.ShowCount {
+		drawEventCount(svg, len(events), timelineY, config.Layout.MarginLeft, config.Layout.MarginLeft+timelineWidth, config)
+	}
+
+	// Draw callout level guides behind everything else
+	if config.Timeline.ShowLevelGuides {
+		drawLevelGuides(svg, timelineY, config.Layout.MarginLeft, config.Layout.MarginLeft+timelineWidth, config)
+	}
+
+	// Equal spacing ignores timestamps, so time-scaled decorations would not line up with the events
+	equalSpacing := strings.EqualFold(config.Timeline.LayoutAlgorithm, "equal_spacing")
+
+	// Bracket related spans of time in the top margin
+	if len(config.Groups) > 0 && !equalSpacing {
+		drawGroupBrackets(svg, rangeStart, rangeEnd, timelineStartX, usableTimelineWidth, config)
+	}
+
+	// Mark fixed reference times with vertical lines behind the events
+	if len(config.Markers) > 0 && !equalSpacing {
+		drawReferenceMarkers(svg, rangeStart, rangeEnd, timelineStartX, usableTimelineWidth, config)
+	}
+
+	// Calculate per-event opacity (all 1.0 unless fading by age)
+	opacities := calculateAgeOpacities(events, config)
+
+	// Flag events after the reference time so they are drawn in the future style
+	if config.Timeline.DistinguishFuture {
+		events = markFutureEvents(events, config)
+	}
+
+	// Number the drawn events in chronological order
+	if config.Timeline.NumberEvents {
+		events = numberEvents(events)
+	}
 
 	// Calculate positions for events based on actual timestamps
 	if len(events) == 1 {
-		// Single event goes in the middle of the usable timeline area
+		// Single event goes in the middle of the usable timeline area, or at its time within a fixed range
 		x := timelineStartX + usableTimelineWidth/2
-		drawEvent(&svg, events[0], x, timelineY, config, 0, []int{x})
+		if rangeEnd.After(rangeStart) && !equalSpacing {
+			proportion := float64(events[0].Timestamp.Sub(rangeStart)) / float64(rangeEnd.Sub(rangeStart))
+			x = proportionalX(timelineStartX, usableTimelineWidth, proportion)
+			if config.Timeline.Reverse {
+				x = 2*timelineStartX + usableTimelineWidth - x
+			}
+		}
+		if config.Timeline.SequenceConnectors {
+			drawSequenceConnectors(svg, []int{x}, timelineY, config)
+		}
+		if config.Timeline.EventTicks {
+			drawEventTicks(svg, []int{x}, timelineY, config)
+		}
+		groupIDs := eventGroupIDs(events, config)
+		openEventGroup(svg, groupIDs, 0)
+		drawEvent(svg, events[0], x, timelineY, config, 0, []int{x}, opacities[0])
+		closeEventGroup(svg, groupIDs)
+		if config.Timeline.DebugLayer {
+			drawPositioningDebugLayer(svg, []int{x}, []int{x}, []int{0}, timelineY, config)
+		}
 	} else {
 		// First calculate ideal callout lengths based on time-proportional positions
 		// This preserves the sophisticated vertical level distribution logic
+		// When every event shares one timestamp there is no range, so they all sit in the middle
 		timeProportionalPositions := make([]int, len(events))
+		timeRange := rangeEnd.Sub(rangeStart)
 		for i, event := range events {
-			timeRange := events[len(events)-1].Timestamp.Sub(events[0].Timestamp)
-			timeFromStart := event.Timestamp.Sub(events[0].Timestamp)
+			if timeRange <= 0 {
+				timeProportionalPositions[i] = timelineStartX + usableTimelineWidth/2
+				continue
+			}
+			timeFromStart := event.Timestamp.Sub(rangeStart)
 			proportion := float64(timeFromStart) / float64(timeRange)
-			timeProportionalPositions[i] = timelineStartX + int(proportion*float64(usableTimelineWidth))
+			timeProportionalPositions[i] = proportionalX(timelineStartX, usableTimelineWidth, proportion)
 		}
 
 		// Position events with constraint-based approach that includes callout optimization
 		eventPositions := calculateSmartPositions(events, timelineStartX, usableTimelineWidth, config.Timeline.MinTextSpacing, config)
 
+		// For reverse chronological layouts, mirror the solved positions so the newest event is on the left.
+		// Positioning and collision solving always run left-to-right in time, so mirroring keeps their
+		// spacing and ordering guarantees intact in the reversed direction.
+		if config.Timeline.Reverse {
+			eventPositions = mirrorPositions(eventPositions, timelineStartX, usableTimelineWidth)
+			debugPrintf("Reverse layout: mirrored positions %v", eventPositions)
+		}
+
 		// Use the globally optimized callout lengths from the smart positioning algorithm
 		var calloutLengths []int
 		if len(globalOptimizedCallouts) == len(events) {
@@ -581,23 +2272,1561 @@ func generateSVG(events []TimelineEvent, config Config) string {
 			copy(calloutLengths, globalOptimizedCallouts)
 			debugPrintf("Using optimized callout lengths: %v", calloutLengths)
 		} else {
-			// Fallback to original calculation if optimization didn't work
+			// Fallback to original calculation if optimization didn't work or was skipped by the layout algorithm
 			calloutLengths = make([]int, len(events))
-			for i := range events {
-				above := i%2 == 0
-				calloutLengths[i] = calculateCalloutLength(timeProportionalPositions[i], i, timeProportionalPositions, above, config, timelineY)
+			for i, event := range events {
+				above := eventAbove(i, config)
+				textHeight := measureEventTextHeight(event, config)
+				calloutLengths[i] = calculateCalloutLength(timeProportionalPositions[i], i, timeProportionalPositions, above, config, timelineY, textHeight)
 			}
 			debugPrintf("Fallback to calculated callout lengths: %v", calloutLengths)
 		}
 
-		// Draw events with collision-free positioning
-		for i, event := range events {
-			drawEventWithCallout(&svg, event, eventPositions[i], timelineY, config, i, eventPositions, calloutLengths[i])
-		}
+		// Stack simultaneous events at their true time instead of spreading them apart. The
+		// stacks are placed after the other events were resolved, so they keep their x and
+		// callout lengths while collisions with the events around them are resolved again.
+		floors := baselineCalloutFloors(events, timelineY, config)
+		pinned := getCalloutOverrides(events, config)
+		var stacked []bool
+		anyStacked := false
+		if strings.EqualFold(config.Timeline.SameTime, "stack") {
+			stackPositions := timeProportionalPositions
+			if config.Timeline.Reverse {
+				stackPositions = mirrorPositions(timeProportionalPositions, timelineStartX, usableTimelineWidth)
+			}
+			stacked = stackSimultaneousEvents(events, eventPositions, calloutLengths, stackPositions, floors, config)
+			for i, isStacked := range stacked {
+				if isStacked {
+					pinned[i] = calloutLengths[i]
+					anyStacked = true
+				}
+			}
+		}
+
+		// Lengthen callouts whose text would sit on or too close to the timeline line. The
+		// longer callouts can run into text that was already clear of it, so resolve
+		// collisions again with the clearance as a floor.
+		if enforceBaselineClearance(calloutLengths, floors) || anyStacked {
+			// The resolver keeps events in chronological order from left to right
+			resolvePositions := eventPositions
+			if config.Timeline.Reverse {
+				resolvePositions = mirrorPositions(eventPositions, timelineStartX, usableTimelineWidth)
+			}
+			resolvePositions, calloutLengths = resolve2DCollisions(events, resolvePositions, calloutLengths, pinned, floors, stacked, timelineY, config)
+			if config.Timeline.Reverse {
+				resolvePositions = mirrorPositions(resolvePositions, timelineStartX, usableTimelineWidth)
+			}
+			eventPositions = resolvePositions
+		}
+
+		// Pinned callout lengths from the CSV always win over computed ones
+		for i, pinned := range getCalloutOverrides(events, config) {
+			if pinned > 0 {
+				calloutLengths[i] = pinned
+			}
+		}
+
+		// Bring text that collision resolution pushed past the canvas edges back inside
+		if config.Timeline.EdgeOverflow != "" {
+			events = fitTextToCanvas(events, eventPositions, calloutLengths, timelineY, config)
+		}
+
+		// Draw the time axis using the same time-proportional scale as the ideal event positions
+		if config.Timeline.ShowAxis && !equalSpacing {
+			drawTimeAxis(svg, rangeStart, rangeEnd, timelineY, timelineStartX, usableTimelineWidth, config)
+		}
+
+		// Draw sequence connectors first so they sit behind callouts and markers
+		if config.Timeline.SequenceConnectors {
+			drawSequenceConnectors(svg, eventPositions, timelineY, config)
+		}
+
+		// Show how long each state lasted behind the duration bars and markers
+		if config.Timeline.FillGaps {
+			drawGapFills(svg, events, eventPositions, timelineY, config)
+		}
+
+		// Draw duration bars underneath the markers so the markers stay visible
+		if config.Timeline.DurationBars {
+			for i, event := range events {
+				drawDurationBar(svg, event, eventPositions[i], timelineY, timeRange, timelineStartX, usableTimelineWidth, config)
+			}
+		}
+
+		// Draw ticks on the line before the markers so each marker sits on its tick
+		if config.Timeline.EventTicks {
+			drawEventTicks(svg, eventPositions, timelineY, config)
+		}
+
+		// Show the detected temporal clusters under the events
+		if config.Timeline.ShowClusters {
+			drawClusterBrackets(svg, events, eventPositions, config)
+		}
+
+		// Draw events with collision-free positioning; later events in the draw order end up on top
+		groupIDs := eventGroupIDs(events, config)
+		lineNudges := calloutLineNudges(eventPositions, calloutLengths, config)
+		for _, i := range calculateDrawOrder(events, config) {
+			openEventGroup(svg, groupIDs, i)
+			drawEventWithCallout(svg, events[i], eventPositions[i], timelineY, config, i, eventPositions, calloutLengths[i], lineNudges[i], opacities[i])
+			closeEventGroup(svg, groupIDs)
+		}
+
+		// Overlay the positioning decisions on top of everything drawn for the events
+		if config.Timeline.DebugLayer {
+			idealPositions := timeProportionalPositions
+			if config.Timeline.Reverse {
+				idealPositions = mirrorPositions(timeProportionalPositions, timelineStartX, usableTimelineWidth)
+			}
+			drawPositioningDebugLayer(svg, idealPositions, eventPositions, calloutLengths, timelineY, config)
+		}
+	}
+
+	if config.Layout.ShowIndex {
+		drawEventIndex(svg, events, config)
+	}
+
+	if len(config.ShapeLegend) > 0 {
+		drawShapeLegend(svg, config)
+	}
+
+	if config.Layout.Watermark != "" && strings.EqualFold(config.Layout.WatermarkPosition, "above") {
+		drawWatermark(svg, config)
+	}
+
+	svg.WriteString("</svg>")
+	return svg.Flush()
+}
+
+// sparklinePadding keeps sparkline ticks at the ends clear of the SVG edges
+const sparklinePadding = 3
+
+// generateSparklineTo renders the compact timeline.mode "sparkline": a background, the baseline
+// across the middle, and one vertical tick per event at its time-proportional position (mirrored
+// for reverse layouts), with no text, markers, or callouts. Highlighted events get a full-height
+// tick. Margins and the layout algorithm do not apply; the size comes from layout.sparkline_width
+// and layout.sparkline_height.
+func generateSparklineTo(w io.Writer, events []TimelineEvent, first, last time.Time, config Config) error {
+	width, height := config.Layout.SparklineWidth, config.Layout.SparklineHeight
+	if width <= 0 {
+		width = 200
+	}
+	if height <= 0 {
+		height = 30
+	}
+	if width <= 2*sparklinePadding || height <= 2*sparklinePadding {
+		return fmt.Errorf("sparkline size %dx%d is too small: both sides must be greater than %d", width, height, 2*sparklinePadding)
+	}
+	if globalManifest != nil {
+		globalManifest.Width, globalManifest.Height = width, height
+	}
+
+	startX, usableWidth := sparklinePadding, width-2*sparklinePadding
+	midY := height / 2
+	tick := maxInt(height/4, 1)
+	tickColor := config.Colors.Events
+	if tickColor == "" {
+		tickColor = config.Colors.Timeline
+	}
+
+	svg := bufio.NewWriter(w)
+	fmt.Fprintf(svg, `<?xml version="1.0" encoding="UTF-8"?>
+<svg width="%d" height="%d" xmlns="http://www.w3.org/2000/svg">
+<rect width="100%%" height="100%%" fill="%s"/>`, width, height, config.Colors.Background)
+	fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
+		startX, midY, startX+usableWidth, midY, config.Colors.Timeline)
+
+	toX := func(time.Time) int { return startX + usableWidth/2 }
+	if last.After(first) {
+		toX = timeToX(first, last, startX, usableWidth, config)
+	}
+	for _, event := range events {
+		x, reach := toX(event.Timestamp), tick
+		if event.Highlighted {
+			reach = midY - 1
+		}
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
+			x, midY-reach, x, midY+reach, tickColor)
+	}
+	debugPrintf("Sparkline: %d events in %dx%d", len(events), width, height)
+
+	svg.WriteString("</svg>")
+	return svg.Flush()
+}
+
+// mergeDenseEvents is the merge pass for timeline.merge_distance. Events are placed at their
+// time-proportional x positions, and each run of events within merge_distance pixels of the
+// run's first event is replaced by a single event. The merged event keeps the first event's
+// time and columns, lists every title in its title column, and records the run length in
+// MergedCount so the marker can show it. This collapses dense clusters instead of forcing
+// their markers apart.
+func mergeDenseEvents(events []TimelineEvent, first, last time.Time, startX, width int, config Config) []TimelineEvent {
+	totalDuration := last.Sub(first)
+	positions := make([]int, len(events))
+	for i, event := range events {
+		if totalDuration > 0 {
+			positions[i] = proportionalX(startX, width, float64(event.Timestamp.Sub(first))/float64(totalDuration))
+		}
+	}
+
+	merged := make([]TimelineEvent, 0, len(events))
+	for start := 0; start < len(events); {
+		end := start + 1
+		for end < len(events) && positions[end]-positions[start] < config.Timeline.MergeDistance {
+			end++
+		}
+
+		if end-start == 1 {
+			merged = append(merged, events[start])
+			start = end
+			continue
+		}
+
+		event := events[start]
+		event.Data = make(map[string]string, len(events[start].Data))
+		for column, value := range events[start].Data {
+			event.Data[column] = value
+		}
+		titles := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			if title := events[i].Data["title"]; title != "" {
+				titles = append(titles, title)
+			}
+			event.Highlighted = event.Highlighted || events[i].Highlighted
+		}
+		event.Data["title"] = strings.Join(titles, ", ")
+		event.MergedCount = end - start
+		debugPrintf("Merged %d events at x=%d: %s", event.MergedCount, positions[start], event.Data["title"])
+
+		merged = append(merged, event)
+		start = end
+	}
+	return merged
+}
+
+// drawMergedCount writes the number of merged events in the centre of a merged event's marker
+func drawMergedCount(svg svgWriter, event TimelineEvent, x, y int, opacity float64, config Config) {
+	if event.MergedCount < 2 {
+		return
+	}
+
+	color := config.Colors.Text
+	if config.EventMarker.FillColor != "" && !strings.EqualFold(config.EventMarker.Fill, "none") {
+		color = contrastingTextColor(config.EventMarker.FillColor)
+	}
+	fontSize := maxInt(config.EventMarker.Size, 8)
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" font-weight="bold" fill="%s"%s>%d</text>`,
+		x, y+fontSize/3, config.Font.Family, fontSize, color, opacityAttr(opacity), event.MergedCount)
+}
+
+// withoutCalendarFeatures turns off the options that read dates or durations from the synthetic
+// Timestamps of columns.numeric_time events, whose labels and spans would be meaningless: the
+// time axis, smart dates, weekend shading, age fading, future and progress styling, cluster
+// brackets, time range limits, group brackets, and reference markers
+func withoutCalendarFeatures(config Config) Config {
+	config.Timeline.ShowAxis = false
+	config.Timeline.SmartDates = false
+	config.Timeline.HighlightWeekends = false
+	config.Timeline.FadeByAge = false
+	config.Timeline.DistinguishFuture = false
+	config.Timeline.ShowProgress = false
+	config.Timeline.ShowClusters = false
+	config.Timeline.RangeStart = ""
+	config.Timeline.RangeEnd = ""
+	config.Groups = nil
+	config.Markers = nil
+	return config
+}
+
+// validateLayoutDimensions reports canvas sizes that leave no room to draw in: a width not
+// larger than the side margins plus the horizontal buffers, or a height not larger than the
+// top and bottom margins. Without it the usable timeline width goes negative and the SVG is
+// drawn with events outside the canvas instead of failing.
+func validateLayoutDimensions(config Config) error {
+	bufferLeft, bufferRight := horizontalBuffers(config)
+	horizontal := config.Layout.MarginLeft + config.Layout.MarginRight + bufferLeft + bufferRight
+	if config.Layout.Width <= horizontal {
+		return fmt.Errorf("layout.width %d leaves no room for the timeline: it must be greater than margin_left + margin_right + horizontal buffers (%d + %d + %d + %d = %d)",
+			config.Layout.Width, config.Layout.MarginLeft, config.Layout.MarginRight, bufferLeft, bufferRight, horizontal)
+	}
+	vertical := config.Layout.MarginTop + config.Layout.MarginBottom
+	if config.Layout.Height <= vertical {
+		return fmt.Errorf("layout.height %d leaves no room for the timeline: it must be greater than margin_top + margin_bottom (%d + %d = %d)",
+			config.Layout.Height, config.Layout.MarginTop, config.Layout.MarginBottom, vertical)
+	}
+	return nil
+}
+
+// horizontalBuffers returns the space kept free before the first and after the last event:
+// timeline.horizontal_buffer_left and _right, each falling back to timeline.horizontal_buffer
+func horizontalBuffers(config Config) (left, right int) {
+	left, right = config.Timeline.HorizontalBuffer, config.Timeline.HorizontalBuffer
+	if config.Timeline.HorizontalBufferLeft != nil {
+		left = *config.Timeline.HorizontalBufferLeft
+	}
+	if config.Timeline.HorizontalBufferRight != nil {
+		right = *config.Timeline.HorizontalBufferRight
+	}
+	return left, right
+}
+
+// calculateAutoWidth returns the SVG width for layout.auto_width: n * min_event_spacing plus
+// the margins and horizontal buffers, so every event gets room without collision compression.
+// Event positions stay time-proportional because they are scaled to the resulting usable width.
+// The width never exceeds layout.max_width; past that limit the usual collision handling
+// compresses the events into the available space. The height is left as configured.
+func calculateAutoWidth(n int, config Config) int {
+	spacing := config.Layout.MinEventSpacing
+	if spacing <= 0 {
+		spacing = config.Timeline.MinTextSpacing
+	}
+	bufferLeft, bufferRight := horizontalBuffers(config)
+	fixed := config.Layout.MarginLeft + config.Layout.MarginRight + bufferLeft + bufferRight
+	width := n*spacing + fixed
+	if config.Layout.MaxWidth > 0 && width > config.Layout.MaxWidth {
+		width = config.Layout.MaxWidth
+	}
+	debugPrintf("Auto width: %d events need width %d (configured %d, max %d)", n, width, config.Layout.Width, config.Layout.MaxWidth)
+	return width
+}
+
+// hasDisplayText reports whether any of the event's display columns has text to draw.
+func hasDisplayText(event TimelineEvent, config Config) bool {
+	for _, elementName := range getColumnOrder(config) {
+		if getElementText(event, elementName, config) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// skipEmptyEvents removes events with no display text for timeline.empty_events "skip".
+func skipEmptyEvents(events []TimelineEvent, config Config) []TimelineEvent {
+	kept := make([]TimelineEvent, 0, len(events))
+	for _, event := range events {
+		if hasDisplayText(event, config) {
+			kept = append(kept, event)
+		}
+	}
+	if skipped := len(events) - len(kept); skipped > 0 {
+		debugPrintf("Skipped %d events without display text", skipped)
+	}
+	return kept
+}
+
+// parseConfigTime parses an optional timestamp from the configuration. Empty or invalid
+// values report false; validateConfig rejects invalid values before rendering starts.
+func parseConfigTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	parsed, err := parseTimestamp(value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
+// getTimeRange returns the start and end of the time range mapped onto the timeline width.
+// It uses timeline.range_start and timeline.range_end when set, falling back to the
+// first and last event timestamps. Events must already be sorted chronologically.
+func getTimeRange(events []TimelineEvent, config Config) (time.Time, time.Time) {
+	start := events[0].Timestamp
+	end := events[len(events)-1].Timestamp
+	if rangeStart, ok := parseConfigTime(config.Timeline.RangeStart); ok {
+		start = rangeStart
+	}
+	if rangeEnd, ok := parseConfigTime(config.Timeline.RangeEnd); ok {
+		end = rangeEnd
+	}
+	return start, end
+}
+
+// applyTimeRange handles events outside the fixed time range according to timeline.out_of_range:
+//   - "drop" (default): events outside the range are removed
+//   - "clamp": events outside the range are moved to the nearest range boundary
+//   - "edge": events outside the range are removed and counted so an indicator can be drawn
+//
+// It returns the remaining events and, for "edge", the number hidden before and after the range.
+func applyTimeRange(events []TimelineEvent, config Config) ([]TimelineEvent, int, int) {
+	rangeStart, hasStart := parseConfigTime(config.Timeline.RangeStart)
+	rangeEnd, hasEnd := parseConfigTime(config.Timeline.RangeEnd)
+	if !hasStart && !hasEnd {
+		return events, 0, 0
+	}
+
+	mode := strings.ToLower(config.Timeline.OutOfRange)
+	filtered := make([]TimelineEvent, 0, len(events))
+	hiddenBefore, hiddenAfter := 0, 0
+	for _, event := range events {
+		before := hasStart && event.Timestamp.Before(rangeStart)
+		after := hasEnd && event.Timestamp.After(rangeEnd)
+		if !before && !after {
+			filtered = append(filtered, event)
+			continue
+		}
+
+		switch mode {
+		case "clamp":
+			if before {
+				event.Timestamp = rangeStart
+			} else {
+				event.Timestamp = rangeEnd
+			}
+			filtered = append(filtered, event)
+		case "edge":
+			if before {
+				hiddenBefore++
+			} else {
+				hiddenAfter++
+			}
+		}
+	}
+
+	debugPrintf("Fixed time range: kept %d of %d events (%d hidden before, %d hidden after, mode=%s)",
+		len(filtered), len(events), hiddenBefore, hiddenAfter, config.Timeline.OutOfRange)
+	if len(filtered) == 0 {
+		fmt.Fprintf(os.Stderr, "Warning: no events fall within the configured time range\n")
+	}
+	return filtered, hiddenBefore, hiddenAfter
+}
+
+// drawOutOfRangeIndicators draws a small arrow and count at each end of the timeline
+// for events hidden outside the fixed time range. In reverse layouts the earlier events
+// are off the right-hand end.
+func drawOutOfRangeIndicators(svg svgWriter, hiddenBefore, hiddenAfter, timelineY, leftX, rightX int, config Config) {
+	hiddenLeft, hiddenRight := hiddenBefore, hiddenAfter
+	if config.Timeline.Reverse {
+		hiddenLeft, hiddenRight = hiddenAfter, hiddenBefore
+	}
+
+	size := 6
+	fontSize := maxInt(config.Font.Size-2, 6)
+	if hiddenLeft > 0 {
+		fmt.Fprintf(svg, `<polygon points="%d,%d %d,%d %d,%d" fill="%s"/>`,
+			leftX-size*2, timelineY, leftX-size, timelineY-size, leftX-size, timelineY+size, config.Colors.Timeline)
+		fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" fill="%s">+%d</text>`,
+			leftX-size*3/2, timelineY-size-4, config.Font.Family, fontSize, config.Colors.Timeline, hiddenLeft)
+	}
+	if hiddenRight > 0 {
+		fmt.Fprintf(svg, `<polygon points="%d,%d %d,%d %d,%d" fill="%s"/>`,
+			rightX+size*2, timelineY, rightX+size, timelineY-size, rightX+size, timelineY+size, config.Colors.Timeline)
+		fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" fill="%s">+%d</text>`,
+			rightX+size*3/2, timelineY-size-4, config.Font.Family, fontSize, config.Colors.Timeline, hiddenRight)
+	}
+}
+
+// drawEventCount draws an "N events" label at the timeline.count_position corner of the
+// timeline line. The label sits in the margin beyond the line end, clear of the out-of-range
+// indicators, so it never overlaps the first or last event's callout and text. When the margin
+// is too narrow for it, the label is moved inwards so it stays on the canvas.
+func drawEventCount(svg svgWriter, count, timelineY, leftX, rightX int, config Config) {
+	label := fmt.Sprintf("%d events", count)
+	if count == 1 {
+		label = "1 event"
+	}
+
+	fontSize := maxInt(config.Font.Size-2, 6)
+	gap := 20 // clears the out-of-range indicator arrows and their labels
+
+	position := strings.ToLower(config.Timeline.CountPosition)
+	width := estimateTextWidth(label, fontSize)
+	edge := 4 // keeps the label off the very edge of the canvas
+	x, anchor := maxInt(leftX-gap, width+edge), "end"
+	if strings.HasSuffix(position, "right") {
+		x, anchor = minInt(rightX+gap, config.Layout.Width-width-edge), "start"
+	}
+	y := timelineY - 4
+	if strings.HasPrefix(position, "bottom") {
+		y = timelineY + fontSize + 2
+	}
+
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="%s" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+		x, y, anchor, config.Font.Family, fontSize, config.Colors.Timeline, label)
+}
+
+// sameDate reports whether every event falls on the same calendar date
+func sameDate(events []TimelineEvent) bool {
+	for _, event := range events[1:] {
+		if event.Timestamp.Format("2006-01-02") != events[0].Timestamp.Format("2006-01-02") {
+			return false
+		}
+	}
+	return true
+}
+
+// gradientDefs returns the <linearGradient> definitions for colors.timeline_gradient and
+// colors.background_gradient, one line each, or "" when neither is set. The timeline gradient
+// uses user space coordinates spanning the line, because a straight line has a zero-height
+// bounding box and would not be painted with a bounding-box gradient.
+func gradientDefs(timelineWidth int, config Config) string {
+	var defs strings.Builder
+	if stops := config.Colors.TimelineGradient; len(stops) == 2 {
+		fmt.Fprintf(&defs, `<linearGradient id="timeline-gradient" gradientUnits="userSpaceOnUse" x1="%d" y1="0" x2="%d" y2="0"><stop offset="0" stop-color="%s"/><stop offset="1" stop-color="%s"/></linearGradient>`+"\n",
+			config.Layout.MarginLeft, config.Layout.MarginLeft+timelineWidth, escapeXML(stops[0]), escapeXML(stops[1]))
+	}
+	if stops := config.Colors.BackgroundGradient; len(stops) == 2 {
+		fmt.Fprintf(&defs, `<linearGradient id="background-gradient" x1="0" y1="0" x2="0" y2="1"><stop offset="0" stop-color="%s"/><stop offset="1" stop-color="%s"/></linearGradient>`+"\n",
+			escapeXML(stops[0]), escapeXML(stops[1]))
+	}
+	return defs.String()
+}
+
+// drawDateHeader draws the date shared by all events centred in the top margin. It is used
+// by timeline.smart_dates, where event timestamps show only the time.
+func drawDateHeader(svg svgWriter, date time.Time, config Config) {
+	text := date.Format("2006-01-02")
+	fontSize := config.Font.Size + 2
+	x := config.Layout.Width / 2
+	y := config.Layout.MarginTop/2 + fontSize/2
+
+	recordManifest(ManifestElement{Type: "text", X: x, Y: y, Width: estimateTextWidth(text, fontSize), Height: fontSize, Text: text})
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" font-weight="bold" fill="%s">%s</text>`,
+		x, y, config.Font.Family, fontSize, config.Colors.Text, text)
+}
+
+// drawGroupBrackets draws each groups entry as a horizontal bracket in the top margin, with
+// end ticks pointing down towards the events and the label centred above it. The span uses
+// the same time-proportional scale as the events and is clipped to the displayed time range;
+// groups entirely outside it are skipped.
+func drawGroupBrackets(svg svgWriter, first, last time.Time, startX, width int, config Config) {
+	if !last.After(first) {
+		return
+	}
+	toX := timeToX(first, last, startX, width, config)
+
+	bracketY := config.Layout.MarginTop - 10
+
+	svg.WriteString(`<g class="group-brackets">`)
+	for _, group := range config.Groups {
+		start, ok := parseConfigTime(group.StartTime)
+		end, okEnd := parseConfigTime(group.EndTime)
+		if !ok || !okEnd || end.Before(first) || start.After(last) {
+			continue
+		}
+		if start.Before(first) {
+			start = first
+		}
+		if end.After(last) {
+			end = last
+		}
+		x1, x2 := toX(start), toX(end)
+		if x1 > x2 {
+			x1, x2 = x2, x1
+		}
+		debugPrintf("Drawing group bracket '%s' from x=%d to x=%d", group.Label, x1, x2)
+		drawBracket(svg, x1, x2, bracketY, 6, group.Label, config)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// eventIndexWidth returns the width reserved for layout.show_index
+func eventIndexWidth(config Config) int {
+	if config.Layout.IndexWidth > 0 {
+		return config.Layout.IndexWidth
+	}
+	return 220
+}
+
+// drawEventIndex lists the events in chronological order, one "time  title" line each, in the
+// layout.show_index column at the right edge of the canvas, from the top margin down to the
+// bottom margin. Lines are truncated to the column width; events that do not fit are
+// summarised in a final "+N more" line.
+func drawEventIndex(svg svgWriter, events []TimelineEvent, config Config) {
+	padding := 10
+	left := config.Layout.Width - eventIndexWidth(config) + padding
+	maxWidth := eventIndexWidth(config) - 2*padding
+	fontSize := maxInt(config.Font.Size-2, 6)
+	lineHeight := fontSize + 4
+	y := config.Layout.MarginTop + fontSize
+	bottom := config.Layout.Height - config.Layout.MarginBottom
+
+	writeLine := func(text, weight string) {
+		if estimateTextWidth(text, fontSize) > maxWidth {
+			text = truncateTextToWidth(text, fontSize, maxWidth)
+		}
+		recordManifest(ManifestElement{Type: "text", X: left + estimateTextWidth(text, fontSize)/2, Y: y, Width: estimateTextWidth(text, fontSize), Height: fontSize, Text: text})
+		fmt.Fprintf(svg, `<text x="%d" y="%d" font-family="%s" font-size="%d" font-weight="%s" fill="%s">%s</text>`,
+			left, y, config.Font.Family, fontSize, weight, config.Colors.Text, escapeXML(text))
+		y += lineHeight
+	}
+
+	svg.WriteString(`<g class="event-index">`)
+	writeLine("Events", "bold")
+	for i, event := range events {
+		// Keep the last line free for the overflow summary unless this is the final event
+		if y+lineHeight > bottom && i < len(events)-1 {
+			writeLine(fmt.Sprintf("+%d more", len(events)-i), "normal")
+			debugPrintf("Event index: %d of %d events listed", i, len(events))
+			break
+		}
+		line := getElementText(event, TimestampColumn, config)
+		if title := getElementText(event, "title", config); title != "" {
+			line += "  " + title
+		}
+		writeLine(line, "normal")
+	}
+	svg.WriteString(`</g>`)
+}
+
+// timeToX returns a function mapping a time onto the time-proportional x scale the events use,
+// mirrored for reverse layouts. The range from first to last must not be empty.
+func timeToX(first, last time.Time, startX, width int, config Config) func(time.Time) int {
+	totalDuration := last.Sub(first)
+	return func(t time.Time) int {
+		x := proportionalX(startX, width, float64(t.Sub(first))/float64(totalDuration))
+		if config.Timeline.Reverse {
+			x = 2*startX + width - x
+		}
+		return x
+	}
+}
+
+// drawReferenceMarkers draws each markers entry as a vertical line across the plot area at its
+// time, with the label rotated to read upwards along the left of the line from the top margin.
+// Markers outside the displayed time range are skipped. A label that would overlap an earlier
+// marker's label is moved down the line below it.
+func drawReferenceMarkers(svg svgWriter, first, last time.Time, startX, width int, config Config) {
+	if !last.After(first) {
+		return
+	}
+	toX := timeToX(first, last, startX, width, config)
+	top, bottom := config.Layout.MarginTop, config.Layout.Height-config.Layout.MarginBottom
+	fontSize := maxInt(config.Font.Size-2, 6)
+
+	// Label footprints already drawn, as horizontal and vertical extents
+	type labelSpan struct{ x1, x2, y1, y2 int }
+	var placed []labelSpan
+
+	svg.WriteString(`<g class="reference-markers">`)
+	for _, marker := range config.Markers {
+		t, ok := parseConfigTime(marker.Time)
+		if !ok || t.Before(first) || t.After(last) {
+			continue
+		}
+		x := toX(t)
+		color := marker.Color
+		if color == "" {
+			color = config.Colors.Timeline
+		}
+		dash := ""
+		switch strings.ToLower(marker.Style) {
+		case "dashed":
+			dash = ` stroke-dasharray="6,4"`
+		case "dotted":
+			dash = ` stroke-dasharray="1,3"`
+		}
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"%s/>`, x, top, x, bottom, color, dash)
+		if marker.Label == "" {
+			continue
+		}
+
+		// The rotated label occupies fontSize pixels left of the line and its text width downwards
+		span := labelSpan{x1: x - 3 - fontSize, x2: x - 3, y1: top + 4}
+		span.y2 = span.y1 + estimateTextWidth(marker.Label, fontSize)
+		for moved := true; moved; {
+			moved = false
+			for _, other := range placed {
+				if span.x1 < other.x2 && other.x1 < span.x2 && span.y1 < other.y2 && other.y1 < span.y2 {
+					span.y2 += other.y2 + 4 - span.y1
+					span.y1 = other.y2 + 4
+					moved = true
+				}
+			}
+		}
+		placed = append(placed, span)
+		debugPrintf("Drawing reference marker '%s' at x=%d, label from y=%d", marker.Label, x, span.y1)
+
+		recordManifest(ManifestElement{Type: "text", X: x - 3 - fontSize/2, Y: (span.y1 + span.y2) / 2, Width: fontSize, Height: span.y2 - span.y1, Text: marker.Label})
+		fmt.Fprintf(svg, `<text x="%d" y="%d" transform="rotate(-90 %d %d)" text-anchor="end" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+			x-3, span.y1, x-3, span.y1, config.Font.Family, fontSize, color, escapeXML(marker.Label))
+	}
+	svg.WriteString(`</g>`)
+}
+
+// drawBracket draws a horizontal bracket from x1 to x2 at y with end ticks of length tick.
+// Positive ticks point down with the label centred above the bracket; negative ticks point
+// up with the label centred below it. An empty label draws just the bracket.
+func drawBracket(svg svgWriter, x1, x2, y, tick int, label string, config Config) {
+	fmt.Fprintf(svg, `<path d="M%d,%d L%d,%d L%d,%d L%d,%d" stroke="%s" stroke-width="1" fill="none"/>`,
+		x1, y+tick, x1, y, x2, y, x2, y+tick, config.Colors.Timeline)
+	if label == "" {
+		return
+	}
+
+	fontSize := maxInt(config.Font.Size-2, 6)
+	labelX, labelY := (x1+x2)/2, y-4
+	if tick < 0 {
+		labelY = y + 4 + fontSize
+	}
+	recordManifest(ManifestElement{Type: "text", X: labelX, Y: labelY, Width: estimateTextWidth(label, fontSize), Height: fontSize, Text: label})
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+		labelX, labelY, config.Font.Family, fontSize, config.Colors.Text, escapeXML(label))
+}
+
+// drawClusterBrackets draws a bracket in the bottom margin under each temporal cluster found
+// by detectClusters, labelled with its event count and time span. The brackets follow the
+// final event positions, so they show what the positioning algorithm treated as a cluster.
+func drawClusterBrackets(svg svgWriter, events []TimelineEvent, positions []int, config Config) {
+	clusters := detectClusters(events, clusterThreshold(config))
+	if len(clusters) == 0 {
+		return
+	}
+	bracketY := config.Layout.Height - config.Layout.MarginBottom + 10
+
+	svg.WriteString(`<g class="cluster-brackets">`)
+	for _, cluster := range clusters {
+		first, last := cluster[0], cluster[len(cluster)-1]
+		x1, x2 := positions[first], positions[first]
+		for _, i := range cluster {
+			x1 = minInt(x1, positions[i])
+			x2 = maxInt(x2, positions[i])
+		}
+		label := fmt.Sprintf("%d events, %s", len(cluster), formatSpan(events[last].Timestamp.Sub(events[first].Timestamp)))
+		debugPrintf("Drawing cluster bracket '%s' from x=%d to x=%d", label, x1, x2)
+		drawBracket(svg, x1, x2, bracketY, -6, label, config)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// debugLayerColor is the color of the --debug-svg layer, chosen to stand out from timeline themes
+const debugLayerColor = "#d6336c"
+
+// drawPositioningDebugLayer draws a <g class="positioning-debug"> layer labelling each marker
+// with the event's index, its ideal time-proportional X, its final X, and its callout length.
+// Labels sit on the side of the line opposite the event's text. Where the positioning moved an
+// event, a dashed line on the timeline joins its ideal and final X.
+func drawPositioningDebugLayer(svg svgWriter, idealPositions, positions, calloutLengths []int, timelineY int, config Config) {
+	fontSize := 8
+	lineHeight := fontSize + 1
+	offset := maxInt(config.EventMarker.Size, 4) + 4
+
+	svg.WriteString(fmt.Sprintf(`<g class="positioning-debug" fill="%s" font-family="monospace" font-size="%d">`, debugLayerColor, fontSize))
+	for i, x := range positions {
+		ideal := idealPositions[i]
+		if ideal != x {
+			svg.WriteString(fmt.Sprintf(`<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1" stroke-dasharray="2,2"/>`,
+				ideal, timelineY, x, timelineY, debugLayerColor))
+			svg.WriteString(fmt.Sprintf(`<circle cx="%d" cy="%d" r="2"/>`, ideal, timelineY))
+		}
+
+		labels := []string{
+			fmt.Sprintf("#%d", i),
+			fmt.Sprintf("ideal %d", ideal),
+			fmt.Sprintf("x %d", x),
+			fmt.Sprintf("callout %d", calloutLengths[i]),
+		}
+		// Text drawn below the line (above=true) gets its labels above the line, and vice versa
+		y := timelineY + offset + fontSize
+		if eventAbove(i, config) {
+			y = timelineY - offset - (len(labels)-1)*lineHeight
+		}
+		svg.WriteString(fmt.Sprintf(`<text x="%d" y="%d" text-anchor="start">`, x+2, y))
+		for j, label := range labels {
+			dy := 0
+			if j > 0 {
+				dy = lineHeight
+			}
+			svg.WriteString(fmt.Sprintf(`<tspan x="%d" dy="%d">%s</tspan>`, x+2, dy, label))
+		}
+		svg.WriteString(`</text>`)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// formatSpan formats a time span compactly to the minute, e.g. "1h30m", or to the second
+// when it is shorter than a minute
+func formatSpan(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return strings.TrimSuffix(d.Round(time.Minute).String(), "0s")
+}
+
+// eventGroupIDs returns the SVG group ID of each event for timeline.group_events, or nil when
+// grouping is off. IDs are "event-<index>", or "event-<value>" when columns.id_column is set and
+// the event has a value there. Values are reduced to letters, digits, '-', '_', and '.', so every
+// ID is a valid XML name; repeated IDs get a "-2", "-3", ... suffix to stay unique.
+func eventGroupIDs(events []TimelineEvent, config Config) []string {
+	if !config.Timeline.GroupEvents {
+		return nil
+	}
+
+	column := strings.ToLower(config.Columns.IDColumn)
+	ids := make([]string, len(events))
+	used := make(map[string]bool, len(events))
+	for i, event := range events {
+		key := strconv.Itoa(i)
+		if column != "" {
+			if value := sanitizeXMLID(event.Data[column]); value != "" {
+				key = value
+			}
+		}
+
+		id := "event-" + key
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("event-%s-%d", key, n)
+		}
+		used[id] = true
+		ids[i] = id
+	}
+	return ids
+}
+
+// sanitizeXMLID keeps the characters of value that are safe in an XML ID, replacing runs of
+// anything else with a single '-' and trimming leading and trailing separators
+func sanitizeXMLID(value string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimSpace(value) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+			b.WriteRune(r)
+		default:
+			if b.Len() > 0 && !strings.HasSuffix(b.String(), "-") {
+				b.WriteByte('-')
+			}
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// openEventGroup starts the group for event i when timeline.group_events is on
+func openEventGroup(svg svgWriter, ids []string, i int) {
+	if ids != nil {
+		fmt.Fprintf(svg, `<g id="%s" class="event">`, ids[i])
+	}
+}
+
+// closeEventGroup ends the group started by openEventGroup
+func closeEventGroup(svg svgWriter, ids []string) {
+	if ids != nil {
+		svg.WriteString("</g>")
+	}
+}
+
+// drawShapeLegend draws the shape_legend entries as a key in the top-right corner, one row
+// per entry. Each sample is drawn by drawEventMarker with the entry's shape, so it matches
+// the event markers exactly; columns.shape_column gives events their shapes.
+func drawShapeLegend(svg svgWriter, config Config) {
+	fontSize := maxInt(config.Font.Size-2, 6)
+	size := config.EventMarker.Size
+	rowHeight := maxInt(3*size, fontSize) + 4 // triangles reach 1.5 * size above their centre
+
+	labelWidth := 0
+	for _, entry := range config.ShapeLegend {
+		labelWidth = maxInt(labelWidth, estimateTextWidth(entry.Label, fontSize))
+	}
+	swatchX := config.Layout.Width - config.Layout.MarginRight - labelWidth - size - 6
+	y := 10 + rowHeight/2
+	debugPrintf("Drawing shape legend with %d entries at x=%d", len(config.ShapeLegend), swatchX)
+
+	svg.WriteString(`<g class="shape-legend">`)
+	for _, entry := range config.ShapeLegend {
+		sample := config
+		sample.EventMarker.Shape = entry.Shape
+		drawEventMarker(svg, swatchX, y, sample, 1.0, false)
+		fmt.Fprintf(svg, `<text x="%d" y="%d" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+			swatchX+size+6, y+fontSize/3, config.Font.Family, fontSize, config.Colors.Text, escapeXML(entry.Label))
+		y += rowHeight
+	}
+	svg.WriteString(`</g>`)
+}
+
+// calculateDrawOrder returns the event indices in the order they should be drawn.
+// Because SVG paints later elements over earlier ones, the last index drawn is on top.
+// Supported timeline.draw_order values:
+//   - "chronological" (default): events are drawn in timestamp order
+//   - "reverse": events are drawn newest first, so earlier events are on top
+//   - "priority": events are drawn by ascending value of the priority column, so the
+//     highest-priority events are on top; missing or non-numeric priorities count as 0
+//
+// Only the draw order changes; event positions and above/below placement are unaffected.
+func calculateDrawOrder(events []TimelineEvent, config Config) []int {
+	order := make([]int, len(events))
+	for i := range order {
+		order[i] = i
+	}
+
+	switch strings.ToLower(config.Timeline.DrawOrder) {
+	case "reverse":
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	case "priority":
+		priorityColumn := strings.ToLower(config.Columns.PriorityColumn)
+		if priorityColumn == "" {
+			priorityColumn = "priority"
+		}
+		priorities := make([]float64, len(events))
+		for i, event := range events {
+			if value, err := strconv.ParseFloat(strings.TrimSpace(event.Data[priorityColumn]), 64); err == nil {
+				priorities[i] = value
+			}
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return priorities[order[a]] < priorities[order[b]]
+		})
+	}
+
+	debugPrintf("Event draw order (%s): %v", config.Timeline.DrawOrder, order)
+	return order
+}
+
+// calculateAgeOpacities returns the opacity for each event. When timeline.fade_by_age is
+// enabled, opacity scales linearly with each event's age relative to the reference time,
+// from 1.0 for events at (or after) the reference time down to timeline.fade_min_opacity
+// for the oldest event. Otherwise every event is fully opaque.
+func calculateAgeOpacities(events []TimelineEvent, config Config) []float64 {
+	opacities := make([]float64, len(events))
+	for i := range opacities {
+		opacities[i] = 1.0
+	}
+	if !config.Timeline.FadeByAge || len(events) == 0 {
+		return opacities
+	}
+
+	reference := time.Now()
+	if parsed, ok := parseConfigTime(config.Timeline.FadeReferenceTime); ok {
+		reference = parsed
+	}
+
+	floor := config.Timeline.FadeMinOpacity
+	if floor <= 0 || floor > 1 {
+		floor = 0.3
+	}
+
+	// The oldest event defines the full fade range
+	oldest := events[0].Timestamp
+	for _, event := range events {
+		if event.Timestamp.Before(oldest) {
+			oldest = event.Timestamp
+		}
+	}
+	maxAge := reference.Sub(oldest)
+	if maxAge <= 0 {
+		return opacities
+	}
+
+	for i, event := range events {
+		age := reference.Sub(event.Timestamp)
+		if age <= 0 {
+			continue
+		}
+		opacities[i] = 1.0 - (1.0-floor)*(float64(age)/float64(maxAge))
+		debugPrintf("Event %d: age %v -> opacity %.2f", i, age, opacities[i])
+	}
+
+	return opacities
+}
+
+// opacityAttr returns an SVG opacity attribute for values below 1.0, or an empty string
+// so fully opaque elements are rendered exactly as before.
+func opacityAttr(opacity float64) string {
+	if opacity >= 1.0 {
+		return ""
+	}
+	return fmt.Sprintf(` opacity="%.2f"`, opacity)
+}
+
+// calculateCalloutLevelHeights returns the callout length for each configured callout level,
+// using the same level spacing as calculateCalloutLength.
+func calculateCalloutLevelHeights(config Config) []int {
+	levels := maxInt(config.Timeline.CalloutLevels, 1)
+	lengthRange := config.Timeline.MaxCalloutLength - config.Timeline.MinCalloutLength
+	levelSpacing := lengthRange / maxInt(config.Timeline.CalloutLevels, 3)
+
+	heights := make([]int, levels)
+	for level := range heights {
+		heights[level] = config.Timeline.MinCalloutLength + level*levelSpacing
+	}
+	return heights
+}
+
+// drawLevelGuides draws faint dashed horizontal lines at the Y position of every callout
+// level on both sides of the timeline. This is a design aid for reading callout heights.
+func drawLevelGuides(svg svgWriter, timelineY, startX, endX int, config Config) {
+	heights := calculateCalloutLevelHeights(config)
+	debugPrintf("Drawing callout level guides at heights %v", heights)
+
+	svg.WriteString(`<g class="level-guides" stroke-opacity="0.25">`)
+	for _, height := range heights {
+		for _, y := range []int{timelineY - height, timelineY + height} {
+			fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1" stroke-dasharray="4,4"/>`,
+				startX, y, endX, y, config.Colors.Timeline)
+		}
+	}
+	svg.WriteString(`</g>`)
+}
+
+// drawWatermark draws the layout.watermark text as a large, rotated, low-opacity label
+// centered on the canvas. The font size scales with the canvas and the text length,
+// capped at half the shorter canvas dimension.
+func drawWatermark(svg svgWriter, config Config) {
+	opacity := config.Layout.WatermarkOpacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = 0.1
+	}
+
+	centerX := config.Layout.Width / 2
+	centerY := config.Layout.Height / 2
+
+	// Size the text to span a little more than the shorter canvas dimension, since it runs diagonally
+	available := float64(minInt(config.Layout.Width, config.Layout.Height)) * 1.2
+	fontSize := int(available / (float64(maxInt(len(config.Layout.Watermark), 1)) * 0.6))
+	fontSize = minInt(fontSize, minInt(config.Layout.Width, config.Layout.Height)/2)
+	fontSize = maxInt(fontSize, config.Font.Size)
+	debugPrintf("Drawing watermark '%s' at (%d, %d) with font size %d", config.Layout.Watermark, centerX, centerY, fontSize)
+
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" dominant-baseline="middle" font-family="%s" font-size="%d" font-weight="bold" fill="%s" opacity="%.2f" transform="rotate(-30 %d %d)">%s</text>`,
+		centerX, centerY, config.Font.Family, fontSize, config.Colors.Text, opacity, centerX, centerY, escapeXML(config.Layout.Watermark))
+}
+
+// axisIntervals lists the candidate tick spacings for the time axis, smallest first
+var axisIntervals = []time.Duration{
+	time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour, 91 * 24 * time.Hour, 365 * 24 * time.Hour,
+}
+
+// calculateAxisTicks returns evenly spaced tick times between first and last, using the
+// smallest interval from axisIntervals that yields no more than maxTicks ticks.
+// Ticks are aligned to whole multiples of the interval (days and longer align to midnight).
+func calculateAxisTicks(first, last time.Time, maxTicks int) ([]time.Time, time.Duration) {
+	totalDuration := last.Sub(first)
+	if totalDuration <= 0 {
+		return []time.Time{first}, 0
+	}
+
+	interval := axisIntervals[len(axisIntervals)-1]
+	for _, candidate := range axisIntervals {
+		if int(totalDuration/candidate) <= maxTicks {
+			interval = candidate
+			break
+		}
+	}
+
+	var start time.Time
+	if interval >= 24*time.Hour {
+		start = time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	} else {
+		start = first.Truncate(interval)
+	}
+	if start.Before(first) {
+		start = start.Add(interval)
+	}
+
+	ticks := []time.Time{}
+	for t := start; !t.After(last); t = t.Add(interval) {
+		ticks = append(ticks, t)
+	}
+	return ticks, interval
+}
+
+// formatAxisLabel formats a tick time with a precision suited to the tick interval
+func formatAxisLabel(t time.Time, interval time.Duration) string {
+	switch {
+	case interval < 6*time.Hour:
+		return t.Format("15:04")
+	case interval < 24*time.Hour:
+		return t.Format("Jan 02 15:04")
+	case interval < 30*24*time.Hour:
+		return t.Format("Jan 02")
+	default:
+		return t.Format("Jan 2006")
+	}
+}
+
+// thinAxisLabels decides which axis labels to keep so that no two kept labels are closer
+// than minGap pixels (measured between label edges). The first and last labels are always
+// kept; when the last label crowds the previously kept one, that earlier label is dropped.
+func thinAxisLabels(xs, widths []int, minGap int) []bool {
+	keep := make([]bool, len(xs))
+	if len(xs) == 0 {
+		return keep
+	}
+
+	keep[0] = true
+	lastKept := 0
+	for i := 1; i < len(xs); i++ {
+		gap := (xs[i] - widths[i]/2) - (xs[lastKept] + widths[lastKept]/2)
+		if gap >= minGap {
+			keep[i] = true
+			lastKept = i
+		}
+	}
+
+	last := len(xs) - 1
+	if !keep[last] {
+		if lastKept != 0 {
+			keep[lastKept] = false
+		}
+		keep[last] = true
+	}
+	return keep
+}
+
+// drawWeekendShading draws a background band over every Saturday, Sunday, and configured
+// holiday between first and last. Bands use the same linear time-to-pixel mapping as the
+// ideal event positions and the time axis, are clipped to the displayed range, and span
+// the area between the top and bottom margins.
+func drawWeekendShading(svg svgWriter, first, last time.Time, startX, width int, config Config) {
+	totalDuration := last.Sub(first)
+	if totalDuration <= 0 {
+		return
+	}
+
+	holidays := make(map[string]bool, len(config.Timeline.Holidays))
+	for _, holiday := range config.Timeline.Holidays {
+		if date, err := parseTimestamp(strings.TrimSpace(holiday)); err == nil {
+			holidays[date.Format("2006-01-02")] = true
+		}
+	}
+
+	color := config.Colors.Weekend
+	if color == "" {
+		color = "#f0f0f0"
+	}
+	top := config.Layout.MarginTop
+	height := config.Layout.Height - config.Layout.MarginTop - config.Layout.MarginBottom
+
+	toX := func(t time.Time) int {
+		x := proportionalX(startX, width, float64(t.Sub(first))/float64(totalDuration))
+		if config.Timeline.Reverse {
+			x = 2*startX + width - x
+		}
+		return x
+	}
+
+	svg.WriteString(`<g class="weekend-shading">`)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	for ; day.Before(last); day = day.AddDate(0, 0, 1) {
+		weekday := day.Weekday()
+		if weekday != time.Saturday && weekday != time.Sunday && !holidays[day.Format("2006-01-02")] {
+			continue
+		}
+
+		bandStart, bandEnd := day, day.AddDate(0, 0, 1)
+		if bandStart.Before(first) {
+			bandStart = first
+		}
+		if bandEnd.After(last) {
+			bandEnd = last
+		}
+		x1, x2 := toX(bandStart), toX(bandEnd)
+		if x1 > x2 {
+			x1, x2 = x2, x1
+		}
+		if x2 <= x1 {
+			continue
+		}
+		fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"/>`, x1, top, x2-x1, height, color)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// futureReferenceTime returns timeline.future_reference_time, or now when it is unset
+func futureReferenceTime(config Config) time.Time {
+	if parsed, ok := parseConfigTime(config.Timeline.FutureReferenceTime); ok {
+		return parsed
+	}
+	return time.Now()
+}
+
+// futureStyleIncludes reports whether timeline.future_style draws the given style, "dashed"
+// or "band"; "both" draws both and an empty style means "dashed"
+func futureStyleIncludes(config Config, style string) bool {
+	configured := strings.ToLower(strings.TrimSpace(config.Timeline.FutureStyle))
+	if configured == "" {
+		configured = "dashed"
+	}
+	return configured == "both" || configured == style
+}
+
+// markFutureEvents returns a copy of events with Future set on every event after the
+// future reference time, leaving the caller's events unchanged
+func markFutureEvents(events []TimelineEvent, config Config) []TimelineEvent {
+	reference := futureReferenceTime(config)
+	marked := make([]TimelineEvent, len(events))
+	future := 0
+	for i, event := range events {
+		event.Future = event.Timestamp.After(reference)
+		if event.Future {
+			future++
+		}
+		marked[i] = event
+	}
+	debugPrintf("Future events after %s: %d of %d", reference.Format("2006-01-02 15:04"), future, len(events))
+	return marked
+}
+
+// numberEvents returns a copy of events with Number set from 1 in chronological order,
+// leaving the caller's events unchanged. Events are already sorted by timestamp.
+func numberEvents(events []TimelineEvent) []TimelineEvent {
+	numbered := make([]TimelineEvent, len(events))
+	for i, event := range events {
+		event.Number = i + 1
+		numbered[i] = event
+	}
+	return numbered
+}
+
+// futureDash returns the stroke-dasharray attribute for future events' callout lines when
+// timeline.future_style draws them dashed, or "" otherwise
+func futureDash(event TimelineEvent, config Config) string {
+	if event.Future && futureStyleIncludes(config, "dashed") {
+		return ` stroke-dasharray="4,3"`
+	}
+	return ""
+}
+
+// drawFutureBand shades the plot area from the future reference time to the end of the
+// timeline in colors.future, using the same time-proportional scale as the events. Nothing
+// is drawn when the reference time is after the last event.
+func drawFutureBand(svg svgWriter, first, last time.Time, startX, width int, config Config) {
+	totalDuration := last.Sub(first)
+	reference := futureReferenceTime(config)
+	if totalDuration <= 0 || !reference.Before(last) {
+		return
+	}
+
+	lineLeft := config.Layout.MarginLeft
+	lineRight := config.Layout.Width - config.Layout.MarginRight
+	splitX := lineLeft
+	if reference.After(first) {
+		splitX = proportionalX(startX, width, float64(reference.Sub(first))/float64(totalDuration))
+	}
+
+	// Reverse layouts have the future on the left
+	futureLeft, futureRight := splitX, lineRight
+	if config.Timeline.Reverse {
+		futureLeft, futureRight = lineLeft, 2*startX+width-splitX
+		if !reference.After(first) {
+			futureRight = lineRight
+		}
+	}
+
+	color := config.Colors.Future
+	if color == "" {
+		color = "#eef3fb"
+	}
+	top := config.Layout.MarginTop
+	height := config.Layout.Height - config.Layout.MarginTop - config.Layout.MarginBottom
+	debugPrintf("Future band from x=%d to x=%d", futureLeft, futureRight)
+	fmt.Fprintf(svg, `<rect class="future-band" x="%d" y="%d" width="%d" height="%d" fill="%s"/>`,
+		futureLeft, top, futureRight-futureLeft, height, color)
+}
+
+// drawProgressOverlay redraws the part of the timeline line before timeline.progress_time
+// (or now) in colors.progress, splitting it at the time-proportional x of that moment. When the
+// moment is after the last event the whole line is elapsed; before the first, nothing is drawn.
+// The overlay is the baseline clipped to the elapsed range, so it also follows an arc.
+func drawProgressOverlay(svg svgWriter, first, last time.Time, timelineY, startX, width int, config Config) {
+	totalDuration := last.Sub(first)
+	if totalDuration <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if parsed, ok := parseConfigTime(config.Timeline.ProgressTime); ok {
+		now = parsed
+	}
+	if now.Before(first) {
+		return
+	}
+
+	lineLeft := config.Layout.MarginLeft
+	lineRight := config.Layout.Width - config.Layout.MarginRight
+	splitX := lineRight
+	if now.Before(last) {
+		splitX = proportionalX(startX, width, float64(now.Sub(first))/float64(totalDuration))
+	}
+
+	// Reverse layouts have the past on the right
+	pastLeft, pastRight := lineLeft, splitX
+	if config.Timeline.Reverse {
+		pastLeft, pastRight = 2*startX+width-splitX, lineRight
+	}
+	debugPrintf("Progress overlay at %s: x=%d to %d", now.Format("2006-01-02 15:04"), pastLeft, pastRight)
+
+	color := config.Colors.Progress
+	if color == "" {
+		color = "#34a853"
+	}
+
+	if strings.EqualFold(config.Timeline.Shape, "arc") {
+		lineWidth := lineRight - lineLeft
+		fmt.Fprintf(svg, `<clipPath id="progress-clip"><rect x="%d" y="0" width="%d" height="%d"/></clipPath>`,
+			pastLeft, pastRight-pastLeft, config.Layout.Height)
+		fmt.Fprintf(svg, `<path d="M%d,%d Q%d,%d %d,%d" stroke="%s" stroke-width="%d" fill="none" clip-path="url(#progress-clip)"/>`,
+			lineLeft, timelineY, lineLeft+lineWidth/2, timelineY-2*arcHeight(config), lineRight, timelineY,
+			color, config.Timeline.LineWidth)
+		return
+	}
+	fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d"/>`,
+		pastLeft, timelineY, pastRight, timelineY, color, config.Timeline.LineWidth)
+}
+
+// drawTimeAxis draws tick marks and labels below the timeline. Tick positions use the
+// same linear time-to-pixel mapping as the ideal event positions. Labels closer than
+// timeline.axis_label_min_gap are skipped, but their tick marks are still drawn.
+func drawTimeAxis(svg svgWriter, first, last time.Time, timelineY, startX, width int, config Config) {
+	maxTicks := config.Timeline.AxisTickCount
+	if maxTicks <= 0 {
+		maxTicks = 10
+	}
+	ticks, interval := calculateAxisTicks(first, last, maxTicks)
+	totalDuration := last.Sub(first)
+	if totalDuration <= 0 || len(ticks) == 0 {
+		return
+	}
+
+	fontSize := maxInt(config.Font.Size-2, 6)
+	xs := make([]int, len(ticks))
+	widths := make([]int, len(ticks))
+	labels := make([]string, len(ticks))
+	for i, tick := range ticks {
+		proportion := float64(tick.Sub(first)) / float64(totalDuration)
+		xs[i] = proportionalX(startX, width, proportion)
+		if config.Timeline.Reverse {
+			xs[i] = startX + width - (xs[i] - startX)
+		}
+		labels[i] = formatAxisLabel(tick, interval)
+		widths[i] = estimateTextWidth(labels[i], fontSize)
+	}
+
+	// Label thinning works left to right, so feed reversed axes in screen order
+	if config.Timeline.Reverse {
+		reverseInts(xs)
+		reverseInts(widths)
+		for i, j := 0, len(labels)-1; i < j; i, j = i+1, j-1 {
+			labels[i], labels[j] = labels[j], labels[i]
+		}
+	}
+	keep := thinAxisLabels(xs, widths, config.Timeline.AxisLabelMinGap)
+	debugPrintf("Time axis: %d ticks every %v, labels kept: %v", len(ticks), interval, keep)
+
+	tickLength := 6
+	svg.WriteString(`<g class="time-axis">`)
+	for i, x := range xs {
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
+			x, timelineY, x, timelineY+tickLength, config.Colors.Timeline)
+		if keep[i] {
+			fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+				x, timelineY+tickLength+fontSize+2, config.Font.Family, fontSize, config.Colors.Timeline, escapeXML(labels[i]))
+		}
+	}
+	svg.WriteString(`</g>`)
+}
+
+// drawSequenceConnectors draws faint vertical ticks from each event marker down to a common
+// baseline, plus the baseline itself spanning the first to last event, to emphasize sequence.
+// The baseline sits at timeline.sequence_connector_y, defaulting to the top of the bottom margin.
+// These connectors are independent of the callout lines and do not affect positioning.
+func drawSequenceConnectors(svg svgWriter, positions []int, timelineY int, config Config) {
+	if len(positions) == 0 {
+		return
+	}
+
+	baselineY := config.Timeline.SequenceConnectorY
+	if baselineY <= 0 {
+		baselineY = config.Layout.Height - config.Layout.MarginBottom
+	}
+	debugPrintf("Drawing %d sequence connectors to baseline y=%d", len(positions), baselineY)
+
+	svg.WriteString(`<g class="sequence-connectors" stroke-opacity="0.35">`)
+	minX, maxX := positions[0], positions[0]
+	for _, x := range positions {
+		markerY, _ := arcPoint(x, timelineY, config)
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
+			x, markerY, x, baselineY, config.Colors.Timeline)
+		minX = minInt(minX, x)
+		maxX = maxInt(maxX, x)
+	}
+	if maxX > minX {
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
+			minX, baselineY, maxX, baselineY, config.Colors.Timeline)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// calloutLineNudges returns the sideways bend for each event's callout line. Lines on the same
+// side of the timeline start together at the line, so markers closer than
+// timeline.callout_line_nudge pixels (4 when unset) would have their lines drawn over each other.
+// The shortest such line stays straight and each longer one bends one more nudge to the right.
+func calloutLineNudges(positions, callouts []int, config Config) []int {
+	nudges := make([]int, len(positions))
+	nudge := 4
+	if config.Timeline.CalloutLineNudge != nil {
+		nudge = *config.Timeline.CalloutLineNudge
+	}
+	if nudge <= 0 {
+		return nudges
+	}
+
+	for i := range positions {
+		rank := 0
+		for j := range positions {
+			if i == j || eventAbove(i, config) != eventAbove(j, config) || absInt(positions[i]-positions[j]) >= nudge {
+				continue
+			}
+			if callouts[j] < callouts[i] || (callouts[j] == callouts[i] && j < i) {
+				rank++
+			}
+		}
+		if rank > 0 {
+			nudges[i] = rank * nudge
+			debugPrintf("Event %d: callout line coincides with %d others, bending it %dpx", i, rank, nudges[i])
+		}
+	}
+	return nudges
+}
+
+// drawEventTicks draws a short vertical tick centred on the timeline line at each event
+// position, like the marks on a ruler, so callouts read as anchored to the line.
+func drawEventTicks(svg svgWriter, positions []int, timelineY int, config Config) {
+	length := config.Timeline.EventTickLength
+	if length <= 0 {
+		length = 12
+	}
+	color := config.Colors.EventTick
+	if color == "" {
+		color = config.Colors.Timeline
+	}
+
+	svg.WriteString(`<g class="event-ticks">`)
+	for _, x := range positions {
+		lineY, _ := arcPoint(x, timelineY, config)
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
+			x, lineY-length/2, x, lineY+length-length/2, color)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// gapFillOpacity keeps timeline.fill_gaps bars faint enough for the line to show through
+const gapFillOpacity = 0.35
+
+// drawGapFills draws the timeline.fill_gaps bars: a translucent bar centred on the line from
+// each event's final x to the next event's, in the event's category color or else
+// colors.duration_bar, as tall as duration_bar_height. The last event has no next event and
+// gets no bar. Reverse layouts work unchanged since the bars span between the two positions.
+func drawGapFills(svg svgWriter, events []TimelineEvent, positions []int, y int, config Config) {
+	barHeight := config.Timeline.DurationBarHeight
+	if barHeight <= 0 {
+		barHeight = 10
+	}
+
+	svg.WriteString(`<g class="gap-fills">`)
+	for i := 0; i < len(events)-1; i++ {
+		x1, x2 := minInt(positions[i], positions[i+1]), maxInt(positions[i], positions[i+1])
+		if x2 == x1 {
+			continue
+		}
+		color := categoryColor(events[i], config)
+		if color == "" {
+			color = config.Colors.DurationBar
+		}
+		if color == "" {
+			color = "#a8c7fa"
+		}
+		fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" fill-opacity="%.2f"/>`,
+			x1, y-barHeight/2, x2-x1, barHeight, color, gapFillOpacity)
+	}
+	svg.WriteString(`</g>`)
+}
+
+// drawDurationBar draws a bar along the timeline from the event's start position to its end time.
+// The bar length is proportional to the event duration using the same time scale as the
+// event positions, and is clipped to the usable timeline so long durations cannot run off it.
+// In reverse chronological layouts the bar extends to the left of the event. Bars narrower than
+// timeline.duration_bar_min_width are widened to it, centered on the event start; events
+// without a positive duration get no bar and keep just their point marker.
+//
+// The event title is placed according to timeline.duration_text_fit:
+//   - "auto" (default): inside the bar when estimateTextWidth fits the bar width, otherwise beside it
+//   - "inside": always centered inside the bar
+//   - "beside": always to the right of the bar
+//   - "none": no title is drawn on the bar
+func drawDurationBar(svg svgWriter, event TimelineEvent, x, y int, timeRange time.Duration, startX, usableWidth int, config Config) {
+	if !event.HasDuration() || timeRange <= 0 {
+		return
+	}
+
+	barWidth := int(float64(event.EndTimestamp.Sub(event.Timestamp)) / float64(timeRange) * float64(usableWidth))
+	barX := x
+	if config.Timeline.Reverse {
+		barWidth = minInt(barWidth, x-startX)
+		barX = x - barWidth
+	} else {
+		barWidth = minInt(barWidth, startX+usableWidth-x)
+	}
+	if minWidth := config.Timeline.DurationBarMinWidth; barWidth < minWidth {
+		// Widen slivers around the start so short durations stay visible and clickable
+		barWidth = minWidth
+		barX = x - minWidth/2
+	}
+	if barWidth <= 0 {
+		return
+	}
+
+	barHeight := config.Timeline.DurationBarHeight
+	if barHeight <= 0 {
+		barHeight = 10
+	}
+	barColor := config.Colors.DurationBar
+	if barColor == "" {
+		barColor = "#a8c7fa"
+	}
+
+	fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" rx="2"/>`,
+		barX, y-barHeight/2, barWidth, barHeight, barColor)
+
+	title := event.Data["title"]
+	fit := strings.ToLower(config.Timeline.DurationTextFit)
+	if title == "" || fit == "none" {
+		return
+	}
+
+	fontSize := config.Font.Size
+	textWidth := estimateTextWidth(title, fontSize)
+	inside := fit == "inside" || ((fit == "" || fit == "auto") && textWidth+4 <= barWidth)
+	debugPrintf("Duration bar for '%s': width=%d, textWidth=%d, inside=%v", title, barWidth, textWidth, inside)
+
+	// Baseline offset that visually centers the text on the bar
+	textY := y + fontSize/3
+	if inside {
+		fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+			barX+barWidth/2, textY, config.Font.Family, fontSize, contrastingTextColor(barColor), escapeXML(title))
+	} else if config.Timeline.Reverse {
+		fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="end" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+			barX-4, textY, config.Font.Family, fontSize, config.Colors.Text, escapeXML(title))
+	} else {
+		fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="start" font-family="%s" font-size="%d" fill="%s">%s</text>`,
+			barX+barWidth+4, textY, config.Font.Family, fontSize, config.Colors.Text, escapeXML(title))
+	}
+}
+
+// contrastingTextColor returns black or white, whichever is more readable on the given
+// background color. Colors that are not "#rgb" or "#rrggbb" hex codes default to black text.
+func contrastingTextColor(background string) string {
+	hex := strings.TrimPrefix(strings.TrimSpace(background), "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	var r, g, b int
+	if len(hex) != 6 {
+		return "#000000"
+	}
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return "#000000"
 	}
 
-	svg.WriteString("</svg>")
-	return svg.String()
+	// Perceived brightness using the ITU-R BT.601 luma coefficients
+	luma := 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+	if luma > 150 {
+		return "#000000"
+	}
+	return "#ffffff"
 }
 
 // estimateTextWidth estimates the width of text in pixels based on character count
@@ -633,7 +3862,7 @@ func estimateEventTextWidth(event TimelineEvent, config Config) int {
 		if elementName != "timestamp" {
 			text := getElementText(event, elementName, config)
 			if text != "" {
-				style := getColumnStyle(elementName, config)
+				style := eventColumnStyle(event, elementName, config)
 				// Account for text wrapping - find longest line
 				words := strings.Fields(text)
 				maxWidth := 20 // Default wrap width
@@ -669,12 +3898,29 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 		return []int{startX + width/2}
 	}
 
-	firstTime := events[0].Timestamp
-	lastTime := events[len(events)-1].Timestamp
+	firstTime, lastTime := getTimeRange(events, config)
 	totalDuration := lastTime.Sub(firstTime)
 
 	debugPrintf("Time range: %s to %s (duration: %s)", firstTime.Format("2006-01-02 15:04"), lastTime.Format("2006-01-02 15:04"), totalDuration)
 
+	layoutAlgorithm := strings.ToLower(config.Timeline.LayoutAlgorithm)
+	if layoutAlgorithm == "equal_spacing" {
+		return calculateEqualSpacingPositions(events, startX, width, config)
+	}
+
+	// Time-proportional layout skips callout optimization entirely, so clear any
+	// callouts left over from a previous run and let generateSVG compute them
+	if layoutAlgorithm == "time_proportional" && totalDuration > 0 {
+		globalOptimizedCallouts = nil
+		positions := make([]int, len(events))
+		for i, event := range events {
+			proportion := float64(event.Timestamp.Sub(firstTime)) / float64(totalDuration)
+			positions[i] = proportionalX(startX, width, proportion)
+		}
+		debugPrintf("Time-proportional layout: positions %v", positions)
+		return positions
+	}
+
 	if totalDuration == 0 {
 		// All events have the same timestamp, distribute evenly
 		debugPrintf("All events have same timestamp, using even distribution")
@@ -692,7 +3938,7 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 	for i, event := range events {
 		eventDuration := event.Timestamp.Sub(firstTime)
 		proportion := float64(eventDuration) / float64(totalDuration)
-		x := startX + int(float64(width)*proportion)
+		x := proportionalX(startX, width, proportion)
 		idealPositions[i] = x
 		debugPrintf("Event %d: %s -> proportion %.3f -> ideal x=%d", i, event.Timestamp.Format("15:04"), proportion, x)
 	}
@@ -701,7 +3947,7 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 	debugPrintf("Step 2: Optimizing callout heights for temporal positioning...")
 
 	// Timeline boundaries for collision detection
-	timelineY := config.Layout.MarginTop + (config.Layout.Height-config.Layout.MarginTop-config.Layout.MarginBottom)/2
+	timelineY := timelineAxisY(config)
 
 	// Try different callout height combinations to find best temporal fit
 	optimizedCallouts, optimizedPositions := optimizeCalloutHeightsForTempo(events, idealPositions, startX, width, timelineY, config)
@@ -716,20 +3962,10 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 		minSpacingConstraints[i] = make([]int, len(events))
 	}
 
-	// Identify temporal cluster for constraint relaxation
-	clusterThreshold := DefaultClusterThreshold
-	eventFirstTime := events[0].Timestamp
-	clusterSize := 1
-
-	for i := 1; i < len(events); i++ {
-		timeDiff := events[i].Timestamp.Sub(eventFirstTime)
-		if timeDiff <= clusterThreshold {
-			clusterSize = i + 1
-		} else {
-			break
-		}
-	}
-	debugPrintf("Final refinement: Using temporal cluster of %d events for relaxed constraints", clusterSize)
+	// Identify temporal clusters for constraint relaxation
+	clusters := detectClusters(events, clusterThreshold(config))
+	clusterIDs := clusterMembership(clusters, len(events))
+	debugPrintf("Final refinement: Using temporal clusters %v for relaxed constraints", clusters)
 
 	// Check for remaining collisions with optimized setup
 	for i := 0; i < len(events); i++ {
@@ -741,12 +3977,13 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 			if detectBoundingBoxOverlap(bbox1, bbox2) {
 				// Use extremely aggressive constraints for temporal cluster events
 				var buffer int
-				if i < clusterSize && j < clusterSize {
-					// Both events in temporal cluster - allow massive overlap for tight clustering
+				sameCluster := clusterIDs[i] >= 0 && clusterIDs[i] == clusterIDs[j]
+				if sameCluster {
+					// Both events in the same temporal cluster - allow massive overlap for tight clustering
 					buffer = UltraAggressiveBuffer // Very negative buffer allows significant text overlap
 					debugPrintf("Using ultra-aggressive temporal clustering constraint for events %d and %d: buffer=%d", i, j, buffer)
-				} else if i < clusterSize || j < clusterSize {
-					// One event in cluster, one outside - use moderate relaxation
+				} else if clusterIDs[i] >= 0 || clusterIDs[j] >= 0 {
+					// At least one event in a cluster, but not the same one - use moderate relaxation
 					buffer = MixedClusterBuffer
 				} else {
 					// Both events outside cluster - use normal buffer
@@ -756,9 +3993,11 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 				requiredSeparation := (bbox1.Width+bbox2.Width)/2 + buffer
 
 				// For temporal cluster events, ensure minimum separation is very small
-				if i < clusterSize && j < clusterSize {
+				if sameCluster {
 					requiredSeparation = maxInt(requiredSeparation, TemporalClusterMinSeparation) // Minimum separation for cluster events
 				}
+				// The guaranteed spacing overrides the cluster relaxation
+				requiredSeparation = maxInt(requiredSeparation, config.Timeline.GuaranteedSpacing)
 
 				// Store constraint: j must be at least this far from i
 				minSpacingConstraints[i][j] = requiredSeparation
@@ -777,6 +4016,11 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 	// Apply final constraint solving if there are any remaining issues
 	finalPositions := solveConstraintBasedPositioning(events, optimizedPositions, minSpacingConstraints, startX, width, config)
 
+	// The solver only approximates its constraints, so enforce the guaranteed floor exactly
+	if config.Timeline.GuaranteedSpacing > 0 {
+		enforceGuaranteedSpacing(finalPositions, config.Timeline.GuaranteedSpacing, startX, width)
+	}
+
 	debugPrintf("Final constraint-satisfied positions: %v", finalPositions)
 	debugPrintf("=== End Constraint-Based Smart Positioning ===")
 
@@ -786,6 +4030,268 @@ func calculateSmartPositions(events []TimelineEvent, startX, width, minSpacing i
 	return finalPositions
 }
 
+// enforceGuaranteedSpacing moves chronologically ordered positions as little as needed so that
+// neighbours are at least spacing pixels apart within [startX, startX+width]. A forward sweep
+// pushes crowded events right, then a backward sweep pulls any pushed past the end back left.
+// When n events cannot fit at that spacing, they are spread evenly across the width instead,
+// which is the largest spacing possible.
+func enforceGuaranteedSpacing(positions []int, spacing, startX, width int) {
+	n := len(positions)
+	if n < 2 {
+		return
+	}
+	if (n-1)*spacing > width {
+		debugPrintf("Guaranteed spacing %d does not fit %d events in %d pixels, spacing evenly", spacing, n, width)
+		for i := range positions {
+			positions[i] = startX + i*width/(n-1)
+		}
+		return
+	}
+
+	for i := 1; i < n; i++ {
+		positions[i] = maxInt(positions[i], positions[i-1]+spacing)
+	}
+	positions[n-1] = minInt(positions[n-1], startX+width)
+	for i := n - 2; i >= 0; i-- {
+		positions[i] = minInt(positions[i], positions[i+1]-spacing)
+	}
+	debugPrintf("Positions with guaranteed spacing %d: %v", spacing, positions)
+}
+
+// proportionalX maps a time proportion (0 at the first event, 1 at the last) to an x position
+// across width pixels from startX. Rounding to the nearest pixel, rather than truncating, avoids
+// shifting events systematically toward the start and puts proportion 1 exactly at startX+width.
+func proportionalX(startX, width int, proportion float64) int {
+	return startX + int(math.Round(proportion*float64(width)))
+}
+
+// calculateEqualSpacingPositions places events evenly across the usable width in chronological
+// order, ignoring the time gaps between them. Only the 2D collision resolver runs afterwards,
+// so the result stays predictable while text overlap is still removed.
+func calculateEqualSpacingPositions(events []TimelineEvent, startX, width int, config Config) []int {
+	debugPrintf("=== Equal Spacing Positioning ===")
+
+	timelineY := timelineAxisY(config)
+
+	positions := make([]int, len(events))
+	for i := range events {
+		positions[i] = startX + (i * width / (len(events) - 1))
+	}
+	debugPrintf("Evenly spaced positions: %v", positions)
+
+	// Start from the density-based callout levels, keeping pinned lengths fixed
+	overrides := getCalloutOverrides(events, config)
+	callouts := make([]int, len(events))
+	for i, event := range events {
+		if overrides[i] > 0 {
+			callouts[i] = overrides[i]
+			continue
+		}
+		callouts[i] = calculateCalloutLength(positions[i], i, positions, eventAbove(i, config), config, timelineY, measureEventTextHeight(event, config))
+	}
+
+	// Text must stay clear of the timeline line however the collisions are resolved
+	floors := baselineCalloutFloors(events, timelineY, config)
+	enforceBaselineClearance(callouts, floors)
+
+	positions, callouts = resolve2DCollisions(events, positions, callouts, overrides, floors, nil, timelineY, config)
+
+	debugPrintf("=== End Equal Spacing Positioning ===")
+
+	globalOptimizedCallouts = callouts
+	return positions
+}
+
+// baselineClearance returns the minimum gap between an event's text and the timeline line:
+// timeline.baseline_clearance when set, otherwise min_callout_length minus the height of the
+// event's text block, never below zero
+func baselineClearance(event TimelineEvent, config Config) int {
+	if clearance := config.Timeline.BaselineClearance; clearance != nil {
+		return *clearance
+	}
+	return maxInt(config.Timeline.MinCalloutLength-measureEventTextHeight(event, config), 0)
+}
+
+// baselineCalloutFloors returns, for each event, the shortest callout length that keeps its
+// text block baselineClearance away from the timeline line. The gap is measured from the edge
+// of the line stroke to the event's bounding box, which moves directly away from the line as
+// the callout grows, so one measurement gives the exact length. The collision resolver never
+// shortens a callout below its floor.
+func baselineCalloutFloors(events []TimelineEvent, timelineY int, config Config) []int {
+	halfLine := (config.Timeline.LineWidth + 1) / 2
+	floors := make([]int, len(events))
+	for i, event := range events {
+		box := calculateEventBoundingBox(event, 0, timelineY, 0, i, config)
+		required := halfLine + baselineClearance(event, config)
+
+		// above=true draws the text below the line
+		missing := box.Bottom - (timelineY - required)
+		if box.Above {
+			missing = timelineY + required - box.Top
+		}
+		floors[i] = maxInt(missing, 0)
+	}
+	return floors
+}
+
+// enforceBaselineClearance lengthens callouts shorter than their baselineCalloutFloors floor
+// and reports whether any callout changed
+func enforceBaselineClearance(callouts, floors []int) bool {
+	changed := false
+	for i, floor := range floors {
+		if callouts[i] < floor {
+			debugPrintf("Event %d text is %d px short of the baseline clearance, callout %d -> %d", i, floor-callouts[i], callouts[i], floor)
+			callouts[i] = floor
+			changed = true
+		}
+	}
+	return changed
+}
+
+// calloutFloor returns the shortest length the collision resolver may give callout i: the
+// configured minimum, or its baseline clearance floor when that is longer
+func calloutFloor(floors []int, i int, config Config) int {
+	if i < len(floors) {
+		return maxInt(config.Timeline.MinCalloutLength, floors[i])
+	}
+	return config.Timeline.MinCalloutLength
+}
+
+// stackSimultaneousEvents places each group of events with exactly the same timestamp at the
+// group's time-proportional x position and gives them increasing callout lengths, so the
+// events stay visually simultaneous. Events alternate sides as usual; on each side the
+// callouts start at the highest callout floor among the stacked events and step by the
+// tallest text block in the group so the stacked text does not overlap. It reports which
+// events were stacked.
+func stackSimultaneousEvents(events []TimelineEvent, positions, callouts, idealPositions, floors []int, config Config) []bool {
+	stacked := make([]bool, len(events))
+	for start := 0; start < len(events); {
+		end := start + 1
+		for end < len(events) && events[end].Timestamp.Equal(events[start].Timestamp) {
+			end++
+		}
+
+		if end-start > 1 {
+			step := 0
+			for i := start; i < end; i++ {
+				step = maxInt(step, measureEventTextHeight(events[i], config)+calloutTextGap(config, eventAbove(i, config))+config.Timeline.TextElementPadding)
+			}
+
+			var levels, bases [2]int // callout level reached and shortest callout on each side of the timeline
+			for i := start; i < end; i++ {
+				side := stackSide(i, config)
+				bases[side] = maxInt(bases[side], calloutFloor(floors, i, config))
+			}
+			for i := start; i < end; i++ {
+				side := stackSide(i, config)
+				positions[i] = idealPositions[start]
+				callouts[i] = bases[side] + levels[side]*step
+				levels[side]++
+				stacked[i] = true
+			}
+			debugPrintf("Stacked %d simultaneous events at x=%d with callout step %d", end-start, idealPositions[start], step)
+		}
+		start = end
+	}
+	return stacked
+}
+
+// stackSide returns the index stackSimultaneousEvents uses for the side of the timeline event i is on
+func stackSide(i int, config Config) int {
+	if eventAbove(i, config) {
+		return 0
+	}
+	return 1
+}
+
+// getCalloutOverrides returns the pinned callout length for each event from columns.callout_column.
+// Events without a positive integer value in that column get 0, meaning the computed length applies.
+// Pinned events keep their exact length during optimization but still take part in collision detection.
+func getCalloutOverrides(events []TimelineEvent, config Config) []int {
+	overrides := make([]int, len(events))
+	if config.Columns.CalloutColumn == "" {
+		return overrides
+	}
+
+	column := strings.ToLower(config.Columns.CalloutColumn)
+	for i, event := range events {
+		value := strings.TrimSpace(event.Data[column])
+		if value == "" {
+			continue
+		}
+		length, err := strconv.Atoi(value)
+		if err != nil || length <= 0 {
+			debugPrintf("Event %d: ignoring invalid callout override '%s'", i, value)
+			continue
+		}
+		overrides[i] = length
+		debugPrintf("Event %d: callout length pinned to %d", i, length)
+	}
+	return overrides
+}
+
+// collisionIterations returns timeline.max_collision_iterations, or defaultIterations when it
+// is unset, so each solver keeps its own built-in limit unless the user overrides it
+func collisionIterations(config Config, defaultIterations int) int {
+	if config.Timeline.MaxCollisionIterations > 0 {
+		return config.Timeline.MaxCollisionIterations
+	}
+	return defaultIterations
+}
+
+// clusterThreshold returns the time window for temporal clustering from timeline.cluster_threshold,
+// falling back to DefaultClusterThreshold when it is unset or invalid
+func clusterThreshold(config Config) time.Duration {
+	value := strings.TrimSpace(config.Timeline.ClusterThreshold)
+	if value == "" {
+		return DefaultClusterThreshold
+	}
+	threshold, err := time.ParseDuration(value)
+	if err != nil || threshold <= 0 {
+		return DefaultClusterThreshold
+	}
+	return threshold
+}
+
+// detectClusters finds every temporal cluster on the timeline. A cluster is a run of two or
+// more consecutive events that all fall within the threshold of the run's first event, so
+// clusters are found anywhere on the timeline, not only at its start. Each cluster is returned
+// as the ascending indices of its events; events outside any cluster are not listed.
+func detectClusters(events []TimelineEvent, threshold time.Duration) [][]int {
+	var clusters [][]int
+	for start := 0; start < len(events); {
+		end := start + 1
+		for end < len(events) && events[end].Timestamp.Sub(events[start].Timestamp) <= threshold {
+			end++
+		}
+
+		if end-start > 1 {
+			cluster := make([]int, 0, end-start)
+			for i := start; i < end; i++ {
+				cluster = append(cluster, i)
+			}
+			clusters = append(clusters, cluster)
+		}
+		start = end
+	}
+	return clusters
+}
+
+// clusterMembership maps each of n events to the index of its cluster in clusters, or -1
+// when the event belongs to no cluster
+func clusterMembership(clusters [][]int, n int) []int {
+	membership := make([]int, n)
+	for i := range membership {
+		membership[i] = -1
+	}
+	for id, cluster := range clusters {
+		for _, i := range cluster {
+			membership[i] = id
+		}
+	}
+	return membership
+}
+
 // optimizeCalloutHeightsForTempo uses backward optimization from constraint solver results
 func optimizeCalloutHeightsForTempo(events []TimelineEvent, idealPositions []int, startX, width, timelineY int, config Config) ([]int, []int) {
 	debugPrintf("--- Backward-Working Callout Height Optimization ---")
@@ -795,27 +4301,18 @@ func optimizeCalloutHeightsForTempo(events []TimelineEvent, idealPositions []int
 	// Step 1: Analyze temporal clustering to determine optimization scope
 	debugPrintf("Step 1: Analyzing temporal clustering...")
 
-	// Find the actual temporal cluster - events within a reasonable time window
-	clusterThreshold := DefaultClusterThreshold // Time window for tight clustering
-	firstTime := events[0].Timestamp
-	clusterSize := 1
-
-	for i := 1; i < n; i++ {
-		timeDiff := events[i].Timestamp.Sub(firstTime)
-		if timeDiff <= clusterThreshold {
-			clusterSize = i + 1
-		} else {
-			break // Found the end of the tight cluster
-		}
-	}
-
-	debugPrintf("Detected temporal cluster: first %d events within %v", clusterSize, clusterThreshold)
-	if clusterSize > 1 {
-		clusterDuration := events[clusterSize-1].Timestamp.Sub(events[0].Timestamp)
-		debugPrintf("Cluster spans: %s to %s (duration: %v)",
-			events[0].Timestamp.Format("15:04"),
-			events[clusterSize-1].Timestamp.Format("15:04"),
-			clusterDuration)
+	// Find every temporal cluster - runs of events within a reasonable time window
+	threshold := clusterThreshold(config)
+	clusters := detectClusters(events, threshold)
+
+	debugPrintf("Detected %d temporal clusters within %v", len(clusters), threshold)
+	for _, cluster := range clusters {
+		first, last := cluster[0], cluster[len(cluster)-1]
+		debugPrintf("Cluster spans: events %d-%d, %s to %s (duration: %v)",
+			first, last,
+			events[first].Timestamp.Format("15:04"),
+			events[last].Timestamp.Format("15:04"),
+			events[last].Timestamp.Sub(events[first].Timestamp))
 	}
 
 	// Step 2: Get baseline constraint-imposed positions with uniform callouts
@@ -824,6 +4321,19 @@ func optimizeCalloutHeightsForTempo(events []TimelineEvent, idealPositions []int
 	minCallout := config.Timeline.MinCalloutLength
 	for i := range uniformCallouts {
 		uniformCallouts[i] = minCallout
+		if oneSidedAxis(config) {
+			// With every event on one side, neighbours can only be separated by staggering
+			// their heights, so start from the density-based levels instead of one row
+			uniformCallouts[i] = calculateCalloutLength(idealPositions[i], i, idealPositions, eventAbove(i, config), config, timelineY, measureEventTextHeight(events[i], config))
+		}
+	}
+
+	// Pinned callout lengths replace the uniform value and are never varied below
+	pinnedCallouts := getCalloutOverrides(events, config)
+	for i, pinned := range pinnedCallouts {
+		if pinned > 0 {
+			uniformCallouts[i] = pinned
+		}
 	}
 
 	// Get what the constraint solver would do with uniform callouts
@@ -831,7 +4341,7 @@ func optimizeCalloutHeightsForTempo(events []TimelineEvent, idealPositions []int
 	debugPrintf("Baseline constraint-imposed positions: %v", baselinePositions)
 
 	// Calculate initial temporal distortion
-	baselineError := calculateTemporalDistortion(events, baselinePositions, idealPositions)
+	baselineError := calculateTemporalDistortion(events, baselinePositions, idealPositions, config)
 	debugPrintf("Baseline temporal distortion: %.1f", baselineError)
 
 	// Step 3: Test callout adjustments to allow movement back toward temporal positions
@@ -850,9 +4360,7 @@ func optimizeCalloutHeightsForTempo(events []TimelineEvent, idealPositions []int
 		maxCallout = minCallout + 100 // Reasonable limit
 	}
 
-	debugPrintf("Using actual temporal cluster size: %d events", clusterSize)
-
-	// Test systematic callout variations that create vertical separation for the ENTIRE cluster
+	// Test systematic callout variations that create vertical separation for each ENTIRE cluster
 	calloutOptions := []int{minCallout, minCallout + 25, minCallout + 50, minCallout + 75}
 	if maxCallout > minCallout+75 {
 		calloutOptions = append(calloutOptions, maxCallout)
@@ -860,35 +4368,45 @@ func optimizeCalloutHeightsForTempo(events []TimelineEvent, idealPositions []int
 
 	debugPrintf("Available callout heights: %v", calloutOptions)
 
-	// Test combinations that create significant vertical separation
-	testCombinations := generateVerticalSeparationCombinations(calloutOptions, clusterSize)
+	// Clusters are optimized one after another, each starting from the best result so far
+	for _, cluster := range clusters {
+		clusterStart, clusterSize := cluster[0], len(cluster)
+		debugPrintf("Optimizing temporal cluster of %d events starting at event %d", clusterSize, clusterStart)
 
-	for i, combo := range testCombinations {
-		debugPrintf("Testing combination %d: %v", i+1, combo)
+		// Test combinations that create significant vertical separation
+		testCombinations := generateVerticalSeparationCombinations(calloutOptions, clusterSize, config.Timeline.OptimizerBudget)
+		clusterBest := make([]int, n)
+		copy(clusterBest, bestCallouts)
 
-		// Create test callout configuration
-		testCallouts := make([]int, n)
-		copy(testCallouts, uniformCallouts)
+		for i, combo := range testCombinations {
+			debugPrintf("Testing combination %d: %v", i+1, combo)
 
-		// Apply combination to clustered events
-		for j := 0; j < len(combo) && j < clusterSize; j++ {
-			testCallouts[j] = combo[j]
-		}
+			// Create test callout configuration
+			testCallouts := make([]int, n)
+			copy(testCallouts, clusterBest)
+
+			// Apply combination to clustered events, leaving pinned callouts untouched
+			for j := 0; j < len(combo) && j < clusterSize; j++ {
+				if pinnedCallouts[clusterStart+j] == 0 {
+					testCallouts[clusterStart+j] = combo[j]
+				}
+			}
 
-		// Simulate what positions would result from this callout configuration
-		testPositions := simulateConstraintSolverResults(events, idealPositions, testCallouts, startX, width, timelineY, config)
+			// Simulate what positions would result from this callout configuration
+			testPositions := simulateConstraintSolverResults(events, idealPositions, testCallouts, startX, width, timelineY, config)
 
-		// Calculate temporal distortion
-		distortion := calculateTemporalDistortion(events, testPositions, idealPositions)
-		debugPrintf("  Resulting positions: %v", testPositions)
-		debugPrintf("  Temporal distortion: %.1f (baseline: %.1f)", distortion, baselineError)
+			// Calculate temporal distortion
+			distortion := calculateTemporalDistortion(events, testPositions, idealPositions, config)
+			debugPrintf("  Resulting positions: %v", testPositions)
+			debugPrintf("  Temporal distortion: %.1f (baseline: %.1f)", distortion, baselineError)
 
-		// Check if this is an improvement
-		if distortion < bestDistortion {
-			bestDistortion = distortion
-			copy(bestCallouts, testCallouts)
-			copy(bestPositions, testPositions)
-			debugPrintf("  NEW BEST! Distortion reduced by %.1f", baselineError-distortion)
+			// Check if this is an improvement
+			if distortion < bestDistortion {
+				bestDistortion = distortion
+				copy(bestCallouts, testCallouts)
+				copy(bestPositions, testPositions)
+				debugPrintf("  NEW BEST! Distortion reduced by %.1f", baselineError-distortion)
+			}
 		}
 	}
 
@@ -924,8 +4442,8 @@ func calculateBestPositionsForCallouts(events []TimelineEvent, callouts, idealPo
 			}{i, float64(absInt(positions[i] - idealPositions[i]))}
 		}
 
-		// Sort by error descending - work on worst cases first
-		sort.Slice(errors, func(i, j int) bool {
+		// Sort by error descending - work on worst cases first; ties stay in event order
+		sort.SliceStable(errors, func(i, j int) bool {
 			return errors[i].error > errors[j].error
 		})
 
@@ -988,24 +4506,13 @@ func hasCollisionsWithCallouts(events []TimelineEvent, positions, callouts []int
 }
 
 // calculateTemporalDistortion measures temporal distortion with dynamic clustering analysis
-func calculateTemporalDistortion(events []TimelineEvent, actualPositions, idealPositions []int) float64 {
+func calculateTemporalDistortion(events []TimelineEvent, actualPositions, idealPositions []int, config Config) float64 {
 	if len(events) <= 1 {
 		return 0.0
 	}
 
-	// Dynamic cluster detection - find events within the default threshold of first event
-	clusterThreshold := DefaultClusterThreshold
-	firstTime := events[0].Timestamp
-	clusterSize := 1
-
-	for i := 1; i < len(events); i++ {
-		timeDiff := events[i].Timestamp.Sub(firstTime)
-		if timeDiff <= clusterThreshold {
-			clusterSize = i + 1
-		} else {
-			break
-		}
-	}
+	// Dynamic cluster detection - find every run of events within the cluster threshold
+	clusterIDs := clusterMembership(detectClusters(events, clusterThreshold(config)), len(events))
 
 	totalDistortion := 0.0
 
@@ -1015,15 +4522,19 @@ func calculateTemporalDistortion(events []TimelineEvent, actualPositions, idealP
 
 		// Dynamic weighting based on actual cluster analysis
 		weight := 1.0
-		if i < clusterSize {
-			// Events within the temporal cluster get high weights
-			// Earlier events in cluster get slightly higher weights
-			weight = 4.0 - (float64(i) * 0.3) // 4.0, 3.7, 3.4, 3.1, 2.8, etc.
-		} else if i == clusterSize {
-			// First event after cluster gets medium weight
+		if clusterIDs[i] >= 0 {
+			// Events within a temporal cluster get high weights
+			// Earlier events in each cluster get slightly higher weights
+			offset := 0
+			for k := i - 1; k >= 0 && clusterIDs[k] == clusterIDs[i]; k-- {
+				offset++
+			}
+			weight = 4.0 - (float64(offset) * 0.3) // 4.0, 3.7, 3.4, 3.1, 2.8, etc.
+		} else if i > 0 && clusterIDs[i-1] >= 0 {
+			// First event after a cluster gets medium weight
 			weight = 1.5
 		}
-		// Events far from cluster keep weight = 1.0
+		// Events far from clusters keep weight = 1.0
 
 		totalDistortion += distortion * weight
 	}
@@ -1031,23 +4542,114 @@ func calculateTemporalDistortion(events []TimelineEvent, actualPositions, idealP
 	return totalDistortion
 }
 
-// simulateConstraintSolverResults predicts what positions would result from constraint solving
-func simulateConstraintSolverResults(events []TimelineEvent, idealPositions, callouts []int, startX, width, timelineY int, config Config) []int {
-	// This simulates the constraint-based positioning process with temporal clustering awareness
+// minEdgeOverflowScale is the smallest font size multiplier timeline.edge_overflow "shrink" uses
+const minEdgeOverflowScale = 0.5
+
+// fitTextToCanvas handles text bounding boxes that extend past [0, layout.width] according to
+// timeline.edge_overflow. "shift" moves the positions in place so the outermost text fits,
+// compressing them when the text is wider than the canvas; this can bring back overlaps the
+// collision resolution removed. "shrink" returns a copy of events whose overflowing events get
+// a smaller TextScale, in 5% steps down to minEdgeOverflowScale. Events are returned unchanged
+// by "shift" and when nothing overflows.
+func fitTextToCanvas(events []TimelineEvent, positions, calloutLengths []int, timelineY int, config Config) []TimelineEvent {
+	canvasWidth := config.Layout.Width
+	overflows := func(box TextBoundingBox) bool {
+		return box.Left < 0 || box.Right > canvasWidth
+	}
 
-	// Step 1: Identify temporal cluster
-	clusterThreshold := DefaultClusterThreshold
-	firstTime := events[0].Timestamp
-	clusterSize := 1
+	switch strings.ToLower(strings.TrimSpace(config.Timeline.EdgeOverflow)) {
+	case "shift":
+		minLeft, maxRight := canvasWidth, 0
+		minPos, maxPos := positions[0], positions[0]
+		for i, event := range events {
+			box := calculateEventBoundingBox(event, positions[i], timelineY, calloutLengths[i], i, config)
+			minLeft = minInt(minLeft, box.Left)
+			maxRight = maxInt(maxRight, box.Right)
+			minPos = minInt(minPos, positions[i])
+			maxPos = maxInt(maxPos, positions[i])
+		}
+		if minLeft >= 0 && maxRight <= canvasWidth {
+			return events
+		}
 
-	for i := 1; i < len(events); i++ {
-		timeDiff := events[i].Timestamp.Sub(firstTime)
-		if timeDiff <= clusterThreshold {
-			clusterSize = i + 1
-		} else {
-			break
+		// Keep the outermost text's reach beyond the outermost markers on both sides
+		startX := minPos - minLeft
+		width := canvasWidth - (maxRight - maxPos) - startX
+		if width < 0 {
+			debugPrintf("Text is too wide to fit the canvas by shifting (reach %d-%d)", minPos-minLeft, maxRight-maxPos)
+			return events
+		}
+		debugPrintf("Text spans [%d,%d], fitting positions into [%d,%d]", minLeft, maxRight, startX, startX+width)
+		fitPositionsToBounds(positions, startX, width)
+		return events
+	case "shrink":
+		shrunk := make([]TimelineEvent, len(events))
+		copy(shrunk, events)
+		for i := range shrunk {
+			box := calculateEventBoundingBox(shrunk[i], positions[i], timelineY, calloutLengths[i], i, config)
+			for step := 19; overflows(box) && float64(step)/20 >= minEdgeOverflowScale; step-- {
+				shrunk[i].TextScale = float64(step) / 20
+				box = calculateEventBoundingBox(shrunk[i], positions[i], timelineY, calloutLengths[i], i, config)
+			}
+			if shrunk[i].TextScale > 0 {
+				debugPrintf("Event %d text shrunk to %.2f to fit the canvas (overflows: %t)", i, shrunk[i].TextScale, overflows(box))
+			}
+		}
+		return shrunk
+	default:
+		return events
+	}
+}
+
+// fitPositionsToBounds brings an ordered position set inside [startX, startX+width].
+// A set wider than the timeline is compressed proportionally, otherwise it is shifted,
+// so relative spacing and order survive instead of piling events onto the boundary.
+func fitPositionsToBounds(positions []int, startX, width int) {
+	if len(positions) == 0 {
+		return
+	}
+
+	minPos, maxPos := positions[0], positions[0]
+	for _, pos := range positions {
+		if pos < minPos {
+			minPos = pos
+		}
+		if pos > maxPos {
+			maxPos = pos
+		}
+	}
+	if minPos >= startX && maxPos <= startX+width {
+		return
+	}
+
+	span := maxPos - minPos
+	if span > width {
+		scale := float64(width) / float64(span)
+		for i := range positions {
+			positions[i] = startX + int(math.Round(float64(positions[i]-minPos)*scale))
 		}
+		debugPrintf("Compressed positions by %.3f to fit bounds", scale)
+		return
+	}
+
+	shift := 0
+	if minPos < startX {
+		shift = startX - minPos
+	} else {
+		shift = startX + width - maxPos
+	}
+	for i := range positions {
+		positions[i] += shift
 	}
+	debugPrintf("Shifted positions by %d to fit bounds", shift)
+}
+
+// simulateConstraintSolverResults predicts what positions would result from constraint solving
+func simulateConstraintSolverResults(events []TimelineEvent, idealPositions, callouts []int, startX, width, timelineY int, config Config) []int {
+	// This simulates the constraint-based positioning process with temporal clustering awareness
+
+	// Step 1: Identify temporal clusters
+	clusterIDs := clusterMembership(detectClusters(events, clusterThreshold(config)), len(events))
 
 	// Step 2: Start with ideal positions
 	positions := make([]int, len(events))
@@ -1067,8 +4669,8 @@ func simulateConstraintSolverResults(events []TimelineEvent, idealPositions, cal
 			bbox2 := calculateEventBoundingBox(events[j], idealPositions[j], timelineY, callouts[j], j, config)
 
 			if detectBoundingBoxOverlap(bbox1, bbox2) {
-				// Both events in temporal cluster - use more relaxed constraints
-				if i < clusterSize && j < clusterSize {
+				// Both events in the same temporal cluster - use more relaxed constraints
+				if clusterIDs[i] >= 0 && clusterIDs[i] == clusterIDs[j] {
 					// For temporal cluster events, allow more overlap - prioritize clustering
 					requiredSeparation := (bbox1.Width+bbox2.Width)/3 + MixedClusterBuffer // Reduced separation
 					constraints[i][j] = requiredSeparation
@@ -1088,7 +4690,7 @@ func simulateConstraintSolverResults(events []TimelineEvent, idealPositions, cal
 	}
 
 	// Step 3: Apply simplified constraint solving (similar to solveConstraintBasedPositioning)
-	maxIterations := 10
+	maxIterations := collisionIterations(config, 10)
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		violations := 0
 
@@ -1123,20 +4725,13 @@ func simulateConstraintSolverResults(events []TimelineEvent, idealPositions, cal
 	}
 
 	// Ensure bounds
-	for i := range positions {
-		if positions[i] < startX {
-			positions[i] = startX
-		}
-		if positions[i] > startX+width {
-			positions[i] = startX + width
-		}
-	}
+	fitPositionsToBounds(positions, startX, width)
 
 	return positions
 }
 
 // generateVerticalSeparationCombinations creates callout combinations that maximize vertical separation
-func generateVerticalSeparationCombinations(calloutOptions []int, clusterSize int) [][]int {
+func generateVerticalSeparationCombinations(calloutOptions []int, clusterSize, budget int) [][]int {
 	combinations := [][]int{}
 
 	// Start with baseline: all minimum
@@ -1260,6 +4855,67 @@ func generateVerticalSeparationCombinations(calloutOptions []int, clusterSize in
 		}
 	}
 
+	return fillCombinationBudget(combinations, calloutOptions, clusterSize, budget)
+}
+
+// fillCombinationBudget tops up the hand-picked patterns with further callout combinations until
+// budget combinations are available. When every combination of calloutOptions across the cluster
+// fits in the budget they are all enumerated; otherwise combinations are sampled with a fixed seed
+// so the same input always produces the same layout. Patterns beyond the budget are dropped,
+// always keeping the all-minimum baseline first. A budget of zero or less leaves the patterns as they are.
+func fillCombinationBudget(combinations [][]int, calloutOptions []int, clusterSize, budget int) [][]int {
+	if budget <= 0 {
+		return combinations
+	}
+	if len(combinations) >= budget {
+		debugPrintf("Optimizer budget %d limits the %d pattern combinations", budget, len(combinations))
+		return combinations[:budget]
+	}
+	if len(calloutOptions) == 0 || clusterSize == 0 {
+		return combinations
+	}
+
+	seen := make(map[string]bool, budget)
+	for _, combo := range combinations {
+		seen[fmt.Sprint(combo)] = true
+	}
+	add := func(combo []int) {
+		if key := fmt.Sprint(combo); !seen[key] {
+			seen[key] = true
+			combinations = append(combinations, combo)
+		}
+	}
+
+	// Count the full set, stopping as soon as it is known to exceed the budget
+	total := 1
+	for i := 0; i < clusterSize && total <= budget; i++ {
+		total *= len(calloutOptions)
+	}
+
+	if total <= budget {
+		// Enumerate every combination as a mixed-radix counter over the callout options
+		for n := 0; n < total; n++ {
+			combo := make([]int, clusterSize)
+			value := n
+			for i := range combo {
+				combo[i] = calloutOptions[value%len(calloutOptions)]
+				value /= len(calloutOptions)
+			}
+			add(combo)
+		}
+		debugPrintf("Optimizer budget %d covers all %d combinations", budget, total)
+		return combinations
+	}
+
+	rng := rand.New(rand.NewSource(int64(clusterSize)))
+	for attempts := 0; len(combinations) < budget && attempts < budget*10; attempts++ {
+		combo := make([]int, clusterSize)
+		for i := range combo {
+			combo[i] = calloutOptions[rng.Intn(len(calloutOptions))]
+		}
+		add(combo)
+	}
+	debugPrintf("Optimizer budget %d: sampled combinations for a %d-event cluster", budget, clusterSize)
 	return combinations
 }
 
@@ -1311,7 +4967,7 @@ func solveConstraintBasedPositioning(events []TimelineEvent, idealPositions []in
 	}
 
 	// Strategy: Use iterative constraint relaxation with proportional scaling
-	maxIterations := 20
+	maxIterations := collisionIterations(config, 20)
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		debugPrintf("Constraint solver iteration %d", iteration+1)
 
@@ -1370,14 +5026,7 @@ func solveConstraintBasedPositioning(events []TimelineEvent, idealPositions []in
 	}
 
 	// Ensure all positions are within bounds
-	for i := range positions {
-		if positions[i] < startX {
-			positions[i] = startX
-		}
-		if positions[i] > startX+width {
-			positions[i] = startX + width
-		}
-	}
+	fitPositionsToBounds(positions, startX, width)
 
 	debugPrintf("Final constraint-solved positions: %v", positions)
 	return positions
@@ -1407,7 +5056,7 @@ func adjustForTextCollisions(events []TimelineEvent, positions []int, config Con
 
 	// Calculate initial text bounds for each event
 	for i, event := range events {
-		above := i%2 == 0
+		above := eventAbove(i, config)
 		textWidth := estimateEventTextWidth(event, config)
 		halfWidth := textWidth / 2
 
@@ -1422,7 +5071,7 @@ func adjustForTextCollisions(events []TimelineEvent, positions []int, config Con
 	}
 
 	// Detect and resolve collisions iteratively
-	maxIterations := 10
+	maxIterations := collisionIterations(config, 10)
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		debugPrintf("--- Collision Detection Iteration %d ---", iteration+1)
 		hasCollisions := false
@@ -1526,9 +5175,122 @@ type TextBoundingBox struct {
 	Above         bool // Whether this is above or below timeline
 }
 
+// eventAbove reports which side of the timeline an event's text goes on. As elsewhere in the
+// drawing code, true places the text below the line. Events alternate sides by index unless
+// timeline.axis_anchor puts the line at the top (all text below) or bottom (all text above).
+func eventAbove(index int, config Config) bool {
+	switch strings.ToLower(config.Timeline.AxisAnchor) {
+	case "top":
+		return true
+	case "bottom":
+		return false
+	default:
+		return index%2 == 0
+	}
+}
+
+// oneSidedAxis reports whether timeline.axis_anchor puts every event on the same side
+func oneSidedAxis(config Config) bool {
+	anchor := strings.ToLower(config.Timeline.AxisAnchor)
+	return anchor == "top" || anchor == "bottom"
+}
+
+// timelineAxisY returns the y position of the timeline line: centred in the plot area, or on
+// its top or bottom edge for timeline.axis_anchor "top" or "bottom"
+func timelineAxisY(config Config) int {
+	switch strings.ToLower(config.Timeline.AxisAnchor) {
+	case "top":
+		return config.Layout.MarginTop
+	case "bottom":
+		return config.Layout.Height - config.Layout.MarginBottom
+	default:
+		return config.Layout.MarginTop + (config.Layout.Height-config.Layout.MarginTop-config.Layout.MarginBottom)/2
+	}
+}
+
+// calloutTextGap returns the gap between the callout line end and the text for one side of
+// the timeline. As elsewhere in the drawing code, above=true places the text below the line,
+// so it uses timeline.callout_text_gap_below; either override falls back to callout_text_gap.
+func calloutTextGap(config Config, above bool) int {
+	gap := config.Timeline.CalloutTextGapAbove
+	if above {
+		gap = config.Timeline.CalloutTextGapBelow
+	}
+	if gap != nil {
+		return *gap
+	}
+	return config.Timeline.CalloutTextGap
+}
+
+// steppedThreshold returns timeline.stepped_threshold, or 10 when it is unset
+func steppedThreshold(config Config) int {
+	if config.Timeline.SteppedThreshold != nil {
+		return *config.Timeline.SteppedThreshold
+	}
+	return 10
+}
+
+// calloutStepY returns the y of the bend in a stepped callout line from the marker at y to
+// the line end at endY. With timeline.callout_step_ratio set, the bend sits that fraction of
+// the way along the drawn line, so 0 and 1 put it at the marker and at the end. Unset, it
+// stays a third of the callout length from the marker. Either way the bend is kept between
+// the two ends so the line never doubles back past its endpoint.
+func calloutStepY(y, endY, calloutLength int, config Config) int {
+	offset := calloutLength / 3
+	if ratio := config.Timeline.CalloutStepRatio; ratio != nil {
+		offset = int(math.Round(float64(endY-y) * *ratio))
+	}
+	return maxInt(minInt(y+offset, maxInt(y, endY)), minInt(y, endY))
+}
+
+// calloutEndpointY returns where the callout line ends for text laid out from textStartY.
+// By default the line stops short of the text edge nearest the timeline; with
+// timeline.callout_direction "into-text" it runs past the text and ends beyond its far edge,
+// pointing back into it. Either way the end keeps padding plus the callout text gap clear.
+func calloutEndpointY(event TimelineEvent, textStartY int, above bool, config Config) int {
+	clearance := config.Timeline.TextElementPadding + calloutTextGap(config, above)
+	positions := calculateConfigurableTextPositions(event, textStartY, above, config)
+	intoText := strings.EqualFold(config.Timeline.CalloutDirection, "into-text")
+
+	endY := textStartY
+	placed := false
+	for _, elementName := range getColumnOrder(config) {
+		position, exists := positions[elementName]
+		if !exists {
+			continue
+		}
+		text := getElementText(event, elementName, config)
+		lines, fontSize, _ := fitOversizeText(text, eventColumnStyle(event, elementName, config), config)
+		bounds := estimateTextBounds(text, fontSize)
+
+		if !intoText {
+			// The first element's height sets the gap at the near edge
+			if above {
+				return textStartY - bounds.Height - clearance
+			}
+			return textStartY + bounds.Height + clearance
+		}
+
+		// Far edge: the lowest wrapped baseline below the line, the highest cap height above it
+		if above {
+			if bottom := position + wrappedExtraHeight(lines, fontSize) + clearance; !placed || bottom > endY {
+				endY = bottom
+			}
+		} else if top := position - bounds.Height - clearance; !placed || top < endY {
+			endY = top
+		}
+		placed = true
+	}
+
+	return endY
+}
+
 // calculateEventBoundingBox calculates the complete 2D bounding box for an event's text
 func calculateEventBoundingBox(event TimelineEvent, x, y int, calloutLength int, index int, config Config) TextBoundingBox {
-	above := index%2 == 0
+	above := eventAbove(index, config)
+
+	// On an arc the callout starts on the curve and leaves it along the normal, as drawn
+	y, slope := arcPoint(x, y, config)
 
 	// Calculate vertical offset from timeline
 	adjustedCalloutLength := calloutLength
@@ -1544,11 +5306,11 @@ func calculateEventBoundingBox(event TimelineEvent, x, y int, calloutLength int,
 		for _, elementName := range columnOrder {
 			text := getElementText(event, elementName, config)
 			if text != "" {
-				style := getColumnStyle(elementName, config)
+				style := eventColumnStyle(event, elementName, config)
 				bounds := estimateTextBounds(text, style.FontSize)
 				// Move the callout endpoint up to provide clearance above the text
 				// Use configurable gap between callout line end and text start
-				eventY -= bounds.Height + config.Timeline.TextElementPadding + config.Timeline.CalloutTextGap
+				eventY -= bounds.Height + config.Timeline.TextElementPadding + calloutTextGap(config, above)
 				break
 			}
 		}
@@ -1559,63 +5321,53 @@ func calculateEventBoundingBox(event TimelineEvent, x, y int, calloutLength int,
 
 	// Find the bounds of all text elements
 	minY, maxY := eventY, eventY
-	maxWidth := 0
+	maxWidth := textBlockWidth(event, config)
 
 	columnOrder := getColumnOrder(config)
 	for _, elementName := range columnOrder {
 		if position, exists := positions[elementName]; exists {
 			text := getElementText(event, elementName, config)
 			if text != "" {
-				style := getColumnStyle(elementName, config)
-
-				// Calculate realistic text width with wrapping for longer text
-				var textWidth int
-				if strings.ToLower(elementName) == "notes" && len(text) > 30 {
-					// For notes, assume reasonable wrapping at about 25-30 characters per line
-					maxLineLength := 30
-					lines := len(text) / maxLineLength
-					if len(text)%maxLineLength > 0 {
-						lines++ // Account for remainder
-					}
-					_ = lines // Variable calculated for potential future use
-					// Use the shorter of wrapped width or a reasonable maximum
-					wrappedWidth := estimateTextWidth(strings.Repeat("A", maxLineLength), style.FontSize)
-					singleLineWidth := estimateTextWidth(text, style.FontSize)
-					textWidth = minInt(wrappedWidth, singleLineWidth)
-					debugPrintf("Event %d, element '%s': text='%s', fontSize=%d, singleLine=%d, wrapped=%d, using=%d",
-						index, elementName, text[:minInt(30, len(text))], style.FontSize, singleLineWidth, wrappedWidth, textWidth)
-				} else {
-					textWidth = estimateTextWidth(text, style.FontSize)
-					debugPrintf("Event %d, element '%s': text='%s', fontSize=%d, textWidth=%d",
-						index, elementName, text, style.FontSize, textWidth)
-				}
-				if textWidth > maxWidth {
-					maxWidth = textWidth
-				}
+				style := eventColumnStyle(event, elementName, config)
+
+				lines, fontSize, _ := fitOversizeText(text, style, config)
+				debugPrintf("Event %d, element '%s': text='%s', fontSize=%d, lines=%d, textWidth=%d",
+					index, elementName, debugSnippet(text), fontSize, len(lines), elementTextWidth(text, style, config))
 
 				// Update vertical bounds
 				if position < minY {
 					minY = position
 				}
-				if position+style.FontSize > maxY {
-					maxY = position + style.FontSize
+				if bottom := position + fontSize + wrappedExtraHeight(lines, fontSize); bottom > maxY {
+					maxY = bottom
 				}
 			}
 		}
 	}
 
+	// A callout that runs into the text ends beyond its far edge, which the box must cover
+	if strings.EqualFold(config.Timeline.CalloutDirection, "into-text") {
+		endY := calloutEndpointY(event, eventY, above, config)
+		minY = minInt(minY, endY)
+		maxY = maxInt(maxY, endY)
+	}
+
 	// Add some padding
 	padding := 5
 	width := maxWidth + (padding * 2)
 	height := (maxY - minY) + (padding * 2)
 
+	// Left or right label placement shifts the text block to one side of the callout
+	anchorX, _ := labelAnchor(normalOffsetX(x, adjustedCalloutLength, slope), config)
+	left := labelLeftEdge(anchorX, maxWidth, config) - padding
+
 	bbox := TextBoundingBox{
-		X:          x,
+		X:          left + width/2,
 		Y:          (minY + maxY) / 2, // Center Y
 		Width:      width,
 		Height:     height,
-		Left:       x - width/2,
-		Right:      x + width/2,
+		Left:       left,
+		Right:      left + width,
 		Top:        minY - padding,
 		Bottom:     maxY + padding,
 		EventIndex: index,
@@ -1641,8 +5393,12 @@ func detectBoundingBoxOverlap(box1, box2 TextBoundingBox) bool {
 	return true
 }
 
-// resolve2DCollisions implements comprehensive 2D bounding box collision detection and resolution
-func resolve2DCollisions(events []TimelineEvent, positions []int, calloutLengths []int, timelineY int, config Config) ([]int, []int) {
+// resolve2DCollisions implements comprehensive 2D bounding box collision detection and resolution.
+// Events with a pinned callout length (pinned[i] > 0, see getCalloutOverrides) keep that length
+// and still take part in collision detection; see resolveVerticalCollisionPinned. Other callouts
+// are never shortened below their floors (see baselineCalloutFloors). Events with fixedX[i] set
+// keep their x position, so only the other event of a pair moves sideways.
+func resolve2DCollisions(events []TimelineEvent, positions []int, calloutLengths []int, pinned, floors []int, fixedX []bool, timelineY int, config Config) ([]int, []int) {
 	debugPrintf("=== 2D Collision Detection ===")
 
 	if len(events) <= 1 {
@@ -1658,9 +5414,14 @@ func resolve2DCollisions(events []TimelineEvent, positions []int, calloutLengths
 	adjustedCallouts := make([]int, len(calloutLengths))
 	copy(adjustedPositions, positions)
 	copy(adjustedCallouts, calloutLengths)
+	for i := range adjustedCallouts {
+		if i < len(pinned) && pinned[i] > 0 {
+			adjustedCallouts[i] = pinned[i]
+		}
+	}
 
 	// Collision resolution strategy: prioritize horizontal separation when min_text_spacing is too small
-	maxIterations := 10
+	maxIterations := collisionIterations(config, 10)
 	for iteration := 0; iteration < maxIterations; iteration++ {
 		debugPrintf("--- 2D Collision Iteration %d ---", iteration+1)
 
@@ -1696,30 +5457,49 @@ func resolve2DCollisions(events []TimelineEvent, positions []int, calloutLengths
 					// Also consider if they already have good vertical separation from dynamic callouts
 					verticalDistance := absInt(adjustedCallouts[i] - adjustedCallouts[j])
 
-					// For events with large time gaps (>1 hour), prefer vertical separation to preserve time proportionality
-					if timeDiff > time.Hour && horizontalDistance > 30 {
-						// These events should be temporally spaced - use vertical separation
-						resolveVerticalCollisionGentle(i, j, &adjustedCallouts, overlapHeight, config)
-						debugPrintf("Resolved with vertical separation (preserving time gap of %v): callouts now [%d, %d]", timeDiff, adjustedCallouts[i], adjustedCallouts[j])
-					} else if horizontalDistance < averageTextWidth/2 {
-						// Events are too close horizontally - check if we can use existing vertical separation
-						if verticalDistance > 30 && boundingBoxes[i].Above == boundingBoxes[j].Above {
-							// Same side with good vertical separation - enhance it slightly
-							resolveVerticalCollisionGentle(i, j, &adjustedCallouts, overlapHeight, config)
-							debugPrintf("Resolved with enhanced vertical separation: callouts now [%d, %d]", adjustedCallouts[i], adjustedCallouts[j])
+					preference := strings.ToLower(config.Timeline.CollisionPreference)
+					if limit := collisionVerticalLimit(config); preference != "horizontal" && adjustedCallouts[i] >= limit && adjustedCallouts[j] >= limit {
+						// Both callouts are already as tall as allowed; more vertical passes would only
+						// keep them there, so separate the events horizontally instead
+						debugPrintf("Callouts %d and %d are at the vertical limit %d, switching to horizontal separation", i, j, limit)
+						preference = "horizontal"
+					}
+
+					switch preference {
+					case "vertical":
+						// Always adjust callout heights, keeping every event at its time position
+						resolveVerticalCollisionPinned(i, j, &adjustedCallouts, &adjustedPositions, pinned, floors, fixedX, overlapHeight, overlapWidth, events, config, minX, maxX)
+						debugPrintf("Resolved with preferred vertical separation: callouts now [%d, %d]", adjustedCallouts[i], adjustedCallouts[j])
+					case "horizontal":
+						// Always move the events apart, keeping their callout heights
+						resolveHorizontalCollisionFixed(i, j, &adjustedPositions, fixedX, overlapWidth, events, config, minX, maxX)
+						debugPrintf("Resolved with preferred horizontal separation: positions now [%d, %d]", adjustedPositions[i], adjustedPositions[j])
+					default:
+						// For events with large time gaps (>1 hour), prefer vertical separation to preserve time proportionality
+						if timeDiff > time.Hour && horizontalDistance > 30 {
+							// These events should be temporally spaced - use vertical separation
+							resolveVerticalCollisionPinned(i, j, &adjustedCallouts, &adjustedPositions, pinned, floors, fixedX, overlapHeight, overlapWidth, events, config, minX, maxX)
+							debugPrintf("Resolved with vertical separation (preserving time gap of %v): callouts now [%d, %d]", timeDiff, adjustedCallouts[i], adjustedCallouts[j])
+						} else if horizontalDistance < averageTextWidth/2 {
+							// Events are too close horizontally - check if we can use existing vertical separation
+							if verticalDistance > 30 && boundingBoxes[i].Above == boundingBoxes[j].Above {
+								// Same side with good vertical separation - enhance it slightly
+								resolveVerticalCollisionPinned(i, j, &adjustedCallouts, &adjustedPositions, pinned, floors, fixedX, overlapHeight, overlapWidth, events, config, minX, maxX)
+								debugPrintf("Resolved with enhanced vertical separation: callouts now [%d, %d]", adjustedCallouts[i], adjustedCallouts[j])
+							} else {
+								// Use minimal horizontal separation to preserve time relationships
+								resolveHorizontalCollisionFixed(i, j, &adjustedPositions, fixedX, overlapWidth, events, config, minX, maxX)
+								debugPrintf("Resolved with minimal horizontal separation (events too close): positions now [%d, %d]", adjustedPositions[i], adjustedPositions[j])
+							}
+						} else if boundingBoxes[i].Above != boundingBoxes[j].Above {
+							// Different sides - use gentle horizontal separation
+							resolveHorizontalCollisionFixed(i, j, &adjustedPositions, fixedX, overlapWidth, events, config, minX, maxX)
+							debugPrintf("Resolved with minimal horizontal separation (different sides): positions now [%d, %d]", adjustedPositions[i], adjustedPositions[j])
 						} else {
-							// Use minimal horizontal separation to preserve time relationships
-							resolveHorizontalCollisionMinimal(i, j, &adjustedPositions, overlapWidth, events, config, minX, maxX)
-							debugPrintf("Resolved with minimal horizontal separation (events too close): positions now [%d, %d]", adjustedPositions[i], adjustedPositions[j])
+							// Same side and reasonable horizontal distance - prefer vertical separation
+							resolveVerticalCollisionPinned(i, j, &adjustedCallouts, &adjustedPositions, pinned, floors, fixedX, overlapHeight, overlapWidth, events, config, minX, maxX)
+							debugPrintf("Resolved with gentle vertical separation: callouts now [%d, %d]", adjustedCallouts[i], adjustedCallouts[j])
 						}
-					} else if boundingBoxes[i].Above != boundingBoxes[j].Above {
-						// Different sides - use gentle horizontal separation
-						resolveHorizontalCollisionMinimal(i, j, &adjustedPositions, overlapWidth, events, config, minX, maxX)
-						debugPrintf("Resolved with minimal horizontal separation (different sides): positions now [%d, %d]", adjustedPositions[i], adjustedPositions[j])
-					} else {
-						// Same side and reasonable horizontal distance - prefer vertical separation
-						resolveVerticalCollisionGentle(i, j, &adjustedCallouts, overlapHeight, config)
-						debugPrintf("Resolved with gentle vertical separation: callouts now [%d, %d]", adjustedCallouts[i], adjustedCallouts[j])
 					}
 				}
 			}
@@ -1766,7 +5546,16 @@ func resolve2DCollisions(events []TimelineEvent, positions []int, calloutLengths
 		prevIdx := positionIndices[i-1]
 
 		if adjustedPositions[currentIdx]-adjustedPositions[prevIdx] < baseMinSpacing {
+			if isFixedX(fixedX, currentIdx) && isFixedX(fixedX, prevIdx) {
+				continue
+			}
 			adjustment := baseMinSpacing - (adjustedPositions[currentIdx] - adjustedPositions[prevIdx])
+			if isFixedX(fixedX, currentIdx) {
+				adjustedPositions[prevIdx] -= adjustment
+				debugPrintf("Enforced marker separation: moved event %d from %d to %d",
+					prevIdx, adjustedPositions[prevIdx]+adjustment, adjustedPositions[prevIdx])
+				continue
+			}
 			adjustedPositions[currentIdx] += adjustment
 			debugPrintf("Enforced marker separation: moved event %d from %d to %d",
 				currentIdx, adjustedPositions[currentIdx]-adjustment, adjustedPositions[currentIdx])
@@ -1785,8 +5574,18 @@ func resolve2DCollisions(events []TimelineEvent, positions []int, calloutLengths
 					i, events[i].Timestamp.Format("15:04"), adjustedPositions[i],
 					j, events[j].Timestamp.Format("15:04"), adjustedPositions[j])
 
-				// Swap positions to maintain chronological order
-				adjustedPositions[i], adjustedPositions[j] = adjustedPositions[j], adjustedPositions[i]
+				// Swap positions to maintain chronological order; an event with a fixed x stays put
+				// and the other one moves just past it instead
+				switch {
+				case isFixedX(fixedX, i) && isFixedX(fixedX, j):
+					continue
+				case isFixedX(fixedX, i):
+					adjustedPositions[j] = adjustedPositions[i] + baseMinSpacing
+				case isFixedX(fixedX, j):
+					adjustedPositions[i] = adjustedPositions[j] - baseMinSpacing
+				default:
+					adjustedPositions[i], adjustedPositions[j] = adjustedPositions[j], adjustedPositions[i]
+				}
 
 				debugPrintf("Corrected positions: Event %d now at %d, Event %d now at %d", i, adjustedPositions[i], j, adjustedPositions[j])
 			}
@@ -1838,9 +5637,19 @@ func resolveVerticalCollision(i, j int, calloutLengths *[]int, overlapHeight int
 	}
 }
 
+// collisionVerticalLimit returns timeline.collision_vertical_limit, or max_callout_length when
+// it is unset
+func collisionVerticalLimit(config Config) int {
+	if config.Timeline.CollisionVerticalLimit > 0 {
+		return config.Timeline.CollisionVerticalLimit
+	}
+	return config.Timeline.MaxCalloutLength
+}
+
 // resolveVerticalCollisionGentle makes smaller adjustments for better visual coherence
-// This works with the existing dynamic callout heights rather than overriding them
-func resolveVerticalCollisionGentle(i, j int, calloutLengths *[]int, overlapHeight int, config Config) {
+// This works with the existing dynamic callout heights rather than overriding them.
+// Neither callout ends up shorter than its calloutFloor.
+func resolveVerticalCollisionGentle(i, j int, calloutLengths *[]int, floors []int, overlapHeight int, config Config) {
 	// Use smaller adjustment for better visual coherence
 	adjustment := (overlapHeight / 3) + 15 // More conservative adjustment
 
@@ -1858,8 +5667,8 @@ func resolveVerticalCollisionGentle(i, j int, calloutLengths *[]int, overlapHeig
 			newJ = config.Timeline.MaxCalloutLength
 		}
 
-		(*calloutLengths)[i] = newI
-		(*calloutLengths)[j] = newJ
+		(*calloutLengths)[i] = maxInt(newI, calloutFloor(floors, i, config))
+		(*calloutLengths)[j] = maxInt(newJ, calloutFloor(floors, j, config))
 	} else {
 		newI := (*calloutLengths)[i] + adjustment/2
 		newJ := (*calloutLengths)[j] - adjustment/2
@@ -1872,9 +5681,42 @@ func resolveVerticalCollisionGentle(i, j int, calloutLengths *[]int, overlapHeig
 			newI = config.Timeline.MaxCalloutLength
 		}
 
-		(*calloutLengths)[i] = newI
-		(*calloutLengths)[j] = newJ
+		(*calloutLengths)[i] = maxInt(newI, calloutFloor(floors, i, config))
+		(*calloutLengths)[j] = maxInt(newJ, calloutFloor(floors, j, config))
+	}
+}
+
+// resolveVerticalCollisionPinned separates a colliding pair vertically while keeping pinned
+// callout lengths fixed. Without pins it is resolveVerticalCollisionGentle. With one pinned
+// event, only the other callout moves: shortened when it is the shorter of the two and can
+// still shrink, lengthened otherwise. When both are pinned, their heights cannot change, so
+// the events are moved apart horizontally instead.
+func resolveVerticalCollisionPinned(i, j int, calloutLengths, positions *[]int, pinned, floors []int, fixedX []bool, overlapHeight, overlapWidth int, events []TimelineEvent, config Config, minX, maxX int) {
+	pinnedI := i < len(pinned) && pinned[i] > 0
+	pinnedJ := j < len(pinned) && pinned[j] > 0
+	switch {
+	case !pinnedI && !pinnedJ:
+		resolveVerticalCollisionGentle(i, j, calloutLengths, floors, overlapHeight, config)
+		return
+	case pinnedI && pinnedJ:
+		debugPrintf("Callouts %d and %d are both pinned, separating horizontally", i, j)
+		resolveHorizontalCollisionFixed(i, j, positions, fixedX, overlapWidth, events, config, minX, maxX)
+		return
+	}
+
+	fixed, moved := i, j
+	if pinnedJ {
+		fixed, moved = j, i
 	}
+	adjustment := (overlapHeight / 3) + 15
+	length := (*calloutLengths)[moved]
+	if length <= (*calloutLengths)[fixed] && length-adjustment >= calloutFloor(floors, moved, config) {
+		length -= adjustment
+	} else {
+		length = minInt(length+adjustment, maxInt(config.Timeline.MaxCalloutLength, length))
+	}
+	debugPrintf("Callout %d is pinned, moving callout %d from %d to %d", fixed, moved, (*calloutLengths)[moved], length)
+	(*calloutLengths)[moved] = length
 }
 
 // resolveHorizontalCollision adjusts horizontal positions to separate events
@@ -1963,6 +5805,28 @@ func resolveHorizontalCollision(i, j int, positions *[]int, overlapWidth int, ev
 	}
 }
 
+// isFixedX reports whether event i must keep its x position during collision resolution
+func isFixedX(fixedX []bool, i int) bool {
+	return i < len(fixedX) && fixedX[i]
+}
+
+// resolveHorizontalCollisionFixed is resolveHorizontalCollisionMinimal for events that may have
+// a fixed x position. When one event of the pair is fixed, the other one moves by the whole
+// separation; when both are fixed, neither moves.
+func resolveHorizontalCollisionFixed(i, j int, positions *[]int, fixedX []bool, overlapWidth int, events []TimelineEvent, config Config, minX, maxX int) {
+	oldI, oldJ := (*positions)[i], (*positions)[j]
+	resolveHorizontalCollisionMinimal(i, j, positions, overlapWidth, events, config, minX, maxX)
+	separation := (*positions)[j] - (*positions)[i]
+	switch {
+	case isFixedX(fixedX, i) && isFixedX(fixedX, j):
+		(*positions)[i], (*positions)[j] = oldI, oldJ
+	case isFixedX(fixedX, i):
+		(*positions)[i], (*positions)[j] = oldI, oldI+separation
+	case isFixedX(fixedX, j):
+		(*positions)[i], (*positions)[j] = oldJ-separation, oldJ
+	}
+}
+
 // resolveHorizontalCollisionMinimal adjusts horizontal positions with minimal movement to preserve time proportionality
 func resolveHorizontalCollisionMinimal(i, j int, positions *[]int, overlapWidth int, events []TimelineEvent, config Config, minX, maxX int) {
 	// Use much smaller adjustments to minimize disruption of time proportionality
@@ -2032,6 +5896,23 @@ func resolveHorizontalCollisionMinimal(i, j int, positions *[]int, overlapWidth
 	}
 }
 
+// mirrorPositions reflects x positions around the center of the range [startX, startX+width].
+// It is used to turn a left-to-right chronological layout into a reverse chronological one.
+func mirrorPositions(positions []int, startX, width int) []int {
+	mirrored := make([]int, len(positions))
+	for i, x := range positions {
+		mirrored[i] = 2*startX + width - x
+	}
+	return mirrored
+}
+
+// reverseInts reverses a slice of integers in place.
+func reverseInts(values []int) {
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+}
+
 // absTimeDuration returns the absolute value of a time duration.
 // For negative durations, it returns the positive equivalent.
 func absTimeDuration(d time.Duration) time.Duration {
@@ -2041,6 +5922,14 @@ func absTimeDuration(d time.Duration) time.Duration {
 	return d
 }
 
+// absFloat returns the absolute value of a float64.
+func absFloat(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
 // minInt returns the smaller of two integers.
 func minInt(a, b int) int {
 	if a < b {
@@ -2098,9 +5987,25 @@ func estimateWrappedTextBounds(lines []string, fontSize int) TextBounds {
 }
 
 // drawEventWithCallout draws a single event with a pre-calculated callout length
-func drawEventWithCallout(svg *strings.Builder, event TimelineEvent, x, y int, config Config, index int, allPositions []int, calloutLength int) {
+func drawEventWithCallout(svg svgWriter, event TimelineEvent, x, y int, config Config, index int, allPositions []int, calloutLength, lineNudge int, opacity float64) {
 	// Determine if event should be above or below the timeline
-	above := index%2 == 0
+	above := eventAbove(index, config)
+
+	// Future events get dashed callout lines and marker borders
+	lineDash := futureDash(event, config)
+	if lineDash != "" {
+		config.EventMarker.StrokeDash = "4,3"
+	}
+
+	// On an arc the marker sits on the curve and the callout leaves it along the curve normal
+	y, slope := arcPoint(x, y, config)
+
+	// Events without text get no callout pointing at blank space
+	if strings.EqualFold(config.Timeline.EmptyEvents, "marker") && !hasDisplayText(event, config) {
+		drawEventMarkerOrIcon(svg, event, x, y, config, opacity)
+		drawAxisTimestamp(svg, event, x, y, above, opacity, config)
+		return
+	}
 
 	// Calculate vertical offset from timeline
 	if !above {
@@ -2112,55 +6017,54 @@ func drawEventWithCallout(svg *strings.Builder, event TimelineEvent, x, y int, c
 	// Store the original eventY for text positioning
 	textStartY := eventY
 
-	// For below-timeline events, adjust eventY (line endpoint) to provide clearance above the first text element
-	if !above {
-		// Get the first text element to determine its height
-		columnOrder := getColumnOrder(config)
-		for _, elementName := range columnOrder {
-			text := getElementText(event, elementName, config)
-			if text != "" {
-				style := getColumnStyle(elementName, config)
-				bounds := estimateTextBounds(text, style.FontSize)
-				// Move the callout endpoint DOWN (closer to timeline) to create a gap above the text
-				// Use configurable gap between callout line end and text start
-				eventY += bounds.Height + config.Timeline.TextElementPadding + config.Timeline.CalloutTextGap
-				break
-			}
-		}
-	} else {
-		// For above-timeline events, adjust eventY (line endpoint) to provide clearance above the first text element
-		// Get the first text element to determine its height
-		columnOrder := getColumnOrder(config)
-		for _, elementName := range columnOrder {
-			text := getElementText(event, elementName, config)
-			if text != "" {
-				style := getColumnStyle(elementName, config)
-				bounds := estimateTextBounds(text, style.FontSize)
-				// Move the callout endpoint UP (closer to timeline) to create a gap above the text
-				// Use configurable gap between callout line end and text start
-				eventY -= bounds.Height + config.Timeline.TextElementPadding + config.Timeline.CalloutTextGap
-				break
-			}
-		}
-	}
+	// Move eventY (line endpoint) clear of the text block, as set by timeline.callout_direction
+	eventY = calloutEndpointY(event, textStartY, above, config)
 
 	// Draw smart connecting line (stepped for better visual clarity)
-	if absInt(calloutLength) > config.Timeline.MinCalloutLength+10 {
+	textX := normalOffsetX(x, eventY-y, slope)
+	var stepped bool
+	switch strings.ToLower(config.Timeline.CalloutLine) {
+	case "stepped":
+		stepped = true
+	case "straight":
+		stepped = false
+	default:
+		stepped = absInt(calloutLength) > config.Timeline.MinCalloutLength+steppedThreshold(config)
+	}
+	if lineNudge != 0 {
+		// Leave the marker straight, then step sideways so the line does not run along a
+		// coincident callout line; the text keeps its position
+		bendY := y + minInt(config.EventMarker.Size+4, absInt(eventY-y))
+		if eventY < y {
+			bendY = y - minInt(config.EventMarker.Size+4, absInt(eventY-y))
+		}
+		bendX := normalOffsetX(x, bendY-y, slope)
+		fmt.Fprintf(svg, `<path d="M%d,%d L%d,%d L%d,%d L%d,%d" stroke="%s" stroke-width="1" fill="none"%s/>`,
+			x, y, bendX, bendY, bendX+lineNudge, bendY, textX+lineNudge, eventY, config.Colors.Timeline, lineDash)
+	} else if stepped {
 		// For longer callouts, use a stepped line to reduce visual clutter
-		midY := y + (calloutLength / 3) // First segment
-		fmt.Fprintf(svg, `<path d="M%d,%d L%d,%d L%d,%d" stroke="%s" stroke-width="1" fill="none"/>`,
-			x, y, x, midY, x, eventY, config.Colors.Timeline)
+		midY := calloutStepY(y, eventY, calloutLength, config) // First segment
+		fmt.Fprintf(svg, `<path d="M%d,%d L%d,%d L%d,%d" stroke="%s" stroke-width="1" fill="none"%s/>`,
+			x, y, normalOffsetX(x, midY-y, slope), midY, textX, eventY, config.Colors.Timeline, lineDash)
 	} else {
 		// For short callouts, use simple straight line
-		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
-			x, y, x, eventY, config.Colors.Timeline)
+		fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"%s/>`,
+			x, y, textX, eventY, config.Colors.Timeline, lineDash)
 	}
+	recordManifest(ManifestElement{Type: "callout", X: x, Y: y, X2: textX + lineNudge, Y2: eventY})
+	textX = normalOffsetX(x, textStartY-y, slope)
 
 	// Draw event marker
-	drawEventMarker(svg, x, y, config)
+	drawEventMarkerOrIcon(svg, event, x, y, config, opacity)
+	drawMergedCount(svg, event, x, y, opacity, config)
+	drawAxisTimestamp(svg, event, x, y, above, opacity, config)
 
 	// Draw title using configurable positioning with the original eventY
 	positions := calculateConfigurableTextPositions(event, textStartY, above, config)
+	gridAnchors := gridTextAnchors(event, textX, config)
+	if config.Timeline.CategoryStripes {
+		drawCategoryStripe(svg, event, textX, positions, opacity, config)
+	}
 
 	// Draw each text element according to display_order
 	columnOrder := getColumnOrder(config)
@@ -2168,42 +6072,152 @@ func drawEventWithCallout(svg *strings.Builder, event TimelineEvent, x, y int, c
 		if position, exists := positions[elementName]; exists {
 			text := getElementText(event, elementName, config)
 			if text != "" {
-				style := getColumnStyle(elementName, config)
+				style := eventColumnStyle(event, elementName, config)
+				if event.Highlighted {
+					style.FontWeight = "bold"
+				}
+				if style.DecorationColumn != "" && !isTruthyValue(event.Data[strings.ToLower(style.DecorationColumn)]) {
+					style.TextDecoration = ""
+				}
 				debugPrintf("Drawing %s '%s' at position (%d, %d) with style: %s %dpx %s",
-					elementName, text, x, position, style.FontFamily, style.FontSize, style.Color)
+					elementName, text, textX, position, style.FontFamily, style.FontSize, style.Color)
 
-				// Use inline styling for maximum flexibility
-				fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" font-weight="%s" fill="%s">%s</text>`,
-					x, position, style.FontFamily, style.FontSize, style.FontWeight, style.Color, escapeXML(text))
+				if point, ok := gridAnchors[elementName]; ok {
+					drawAnchoredTextElement(svg, point.X, position, point.Anchor, text, style, opacity, config)
+				} else {
+					drawTextElement(svg, textX, position, text, style, opacity, config)
+				}
 			}
 		}
 	}
 }
 
+// drawAxisTimestamp draws the event timestamp in a small font centred on the marker, on the
+// opposite side of the line from the callout text, when timeline.timestamp_on_axis is set.
+// Text placed with above=true hangs below the line, so the timestamp then goes above it.
+func drawAxisTimestamp(svg svgWriter, event TimelineEvent, x, y int, above bool, opacity float64, config Config) {
+	if !config.Timeline.TimestampOnAxis {
+		return
+	}
+
+	text := getElementText(event, TimestampColumn, config)
+	style := eventColumnStyle(event, TimestampColumn, config)
+	fontSize := maxInt(style.FontSize-2, 6)
+	gap := config.EventMarker.Size + 4
+
+	textY := y + gap + fontSize
+	if above {
+		textY = y - gap
+	}
+
+	recordManifest(ManifestElement{Type: "text", X: x, Y: textY, Width: estimateTextWidth(text, fontSize), Height: fontSize, Text: text})
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" fill="%s"%s>%s</text>`,
+		x, textY, style.FontFamily, fontSize, style.Color, opacityAttr(opacity), escapeXML(text))
+}
+
+// categoryStripeWidth and categoryStripeGap size the timeline.category_stripes rule and its
+// distance from the left edge of the text block
+const (
+	categoryStripeWidth = 3
+	categoryStripeGap   = 3
+)
+
+// categoryColor returns the categories color for the event's columns.category_column value,
+// or "" when the event has no category or the category has no color
+func categoryColor(event TimelineEvent, config Config) string {
+	if config.Columns.CategoryColumn == "" {
+		return ""
+	}
+	category := strings.TrimSpace(event.Data[strings.ToLower(config.Columns.CategoryColumn)])
+	if category == "" {
+		return ""
+	}
+	for _, style := range config.Categories {
+		if strings.EqualFold(strings.TrimSpace(style.Name), category) {
+			return style.Color
+		}
+	}
+	return ""
+}
+
+// drawCategoryStripe draws a vertical rule in the event's category color just left of its text
+// block, which starts where calculateEventBoundingBox puts the box's left edge. The rule runs
+// from the cap height of the highest element to the last wrapped line of the lowest, given
+// the element baselines in positions. Events without a category color get no stripe.
+func drawCategoryStripe(svg svgWriter, event TimelineEvent, textX int, positions map[string]int, opacity float64, config Config) {
+	color := categoryColor(event, config)
+	if color == "" || len(positions) == 0 {
+		return
+	}
+
+	top, bottom, placed := 0, 0, false
+	for elementName, position := range positions {
+		text := getElementText(event, elementName, config)
+		lines, fontSize, _ := fitOversizeText(text, eventColumnStyle(event, elementName, config), config)
+		elementTop := position - estimateTextBounds(text, fontSize).Height
+		elementBottom := position + wrappedExtraHeight(lines, fontSize) + fontSize/4
+		if !placed || elementTop < top {
+			top = elementTop
+		}
+		if !placed || elementBottom > bottom {
+			bottom = elementBottom
+		}
+		placed = true
+	}
+
+	anchorX, _ := labelAnchor(textX, config)
+	x := labelLeftEdge(anchorX, textBlockWidth(event, config), config) - categoryStripeGap - categoryStripeWidth
+	fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"%s/>`,
+		x, top, categoryStripeWidth, bottom-top, color, opacityAttr(opacity))
+}
+
 // drawEvent draws a single event on the timeline with configurable text elements
-func drawEvent(svg *strings.Builder, event TimelineEvent, x, y int, config Config, index int, allPositions []int) {
+func drawEvent(svg svgWriter, event TimelineEvent, x, y int, config Config, index int, allPositions []int, opacity float64) {
 	// Determine if event should be above or below the timeline
-	above := index%2 == 0
+	above := eventAbove(index, config)
+
+	// Future events get dashed callout lines and marker borders
+	lineDash := futureDash(event, config)
+	if lineDash != "" {
+		config.EventMarker.StrokeDash = "4,3"
+	}
 
 	// Calculate callout length based on collision avoidance and boundary constraints
-	calloutLength := calculateCalloutLength(x, index, allPositions, above, config, y)
+	calloutLength := calculateCalloutLength(x, index, allPositions, above, config, y, measureEventTextHeight(event, config))
 
 	// Calculate vertical offset from timeline
 	if !above {
 		calloutLength = -calloutLength
 	}
 
+	y, slope := arcPoint(x, y, config)
+
+	// Events without text get no callout pointing at blank space
+	if strings.EqualFold(config.Timeline.EmptyEvents, "marker") && !hasDisplayText(event, config) {
+		drawEventMarkerOrIcon(svg, event, x, y, config, opacity)
+		drawAxisTimestamp(svg, event, x, y, above, opacity, config)
+		return
+	}
+
 	eventY := y + calloutLength
+	textX := normalOffsetX(x, calloutLength, slope)
 
 	// Draw connecting line
-	fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"/>`,
-		x, y, x, eventY, config.Colors.Timeline)
+	fmt.Fprintf(svg, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="1"%s/>`,
+		x, y, textX, eventY, config.Colors.Timeline, lineDash)
+	recordManifest(ManifestElement{Type: "callout", X: x, Y: y, X2: textX, Y2: eventY})
 
 	// Draw event marker
-	drawEventMarker(svg, x, y, config)
+	drawEventMarkerOrIcon(svg, event, x, y, config, opacity)
+	drawMergedCount(svg, event, x, y, opacity, config)
+	drawAxisTimestamp(svg, event, x, y, above, opacity, config)
 
 	// Draw title using configurable positioning
 	positions := calculateConfigurableTextPositions(event, eventY, above, config)
+	gridAnchors := gridTextAnchors(event, textX, config)
+	if config.Timeline.CategoryStripes {
+		drawCategoryStripe(svg, event, textX, positions, opacity, config)
+	}
 
 	// Draw each text element according to display_order
 	columnOrder := getColumnOrder(config)
@@ -2211,16 +6225,188 @@ func drawEvent(svg *strings.Builder, event TimelineEvent, x, y int, config Confi
 		if position, exists := positions[elementName]; exists {
 			text := getElementText(event, elementName, config)
 			if text != "" {
-				style := getColumnStyle(elementName, config)
+				style := eventColumnStyle(event, elementName, config)
+				if event.Highlighted {
+					style.FontWeight = "bold"
+				}
+				if style.DecorationColumn != "" && !isTruthyValue(event.Data[strings.ToLower(style.DecorationColumn)]) {
+					style.TextDecoration = ""
+				}
 				debugPrintf("Drawing %s '%s' at position (%d, %d) with style: %s %dpx %s",
-					elementName, text, x, position, style.FontFamily, style.FontSize, style.Color)
+					elementName, text, textX, position, style.FontFamily, style.FontSize, style.Color)
+
+				if point, ok := gridAnchors[elementName]; ok {
+					drawAnchoredTextElement(svg, point.X, position, point.Anchor, text, style, opacity, config)
+				} else {
+					drawTextElement(svg, textX, position, text, style, opacity, config)
+				}
+			}
+		}
+	}
+}
+
+// arcPoint maps an x position onto the timeline baseline. For timeline.shape "arc" the baseline
+// is a quadratic curve from the left to the right margin with its control point centred
+// 2*arc_height above baselineY, so the apex lies arc_height above it. It returns the curve's y
+// at x and the tangent slope dy/dx there; for the default straight line it returns baselineY and 0.
+func arcPoint(x, baselineY int, config Config) (int, float64) {
+	if !strings.EqualFold(config.Timeline.Shape, "arc") {
+		return baselineY, 0
+	}
+
+	leftX := config.Layout.MarginLeft
+	span := config.Layout.Width - config.Layout.MarginLeft - config.Layout.MarginRight
+	if span <= 0 {
+		return baselineY, 0
+	}
+
+	// With the control point centred, x is linear in the curve parameter t
+	t := float64(x-leftX) / float64(span)
+	t = math.Max(0, math.Min(1, t))
+	height := float64(arcHeight(config))
+
+	y := float64(baselineY) - 4*height*t*(1-t)
+	slope := -4 * height * (1 - 2*t) / float64(span)
+	return int(math.Round(y)), slope
+}
+
+// arcHeight returns timeline.arc_height, or 60 when it is unset
+func arcHeight(config Config) int {
+	if config.Timeline.ArcHeight != nil {
+		return *config.Timeline.ArcHeight
+	}
+	return 60
+}
+
+// normalOffsetX returns the x coordinate reached by moving dy pixels vertically from x along
+// the normal of a baseline with the given tangent slope. On a straight line it returns x.
+func normalOffsetX(x, dy int, slope float64) int {
+	return x - int(math.Round(slope*float64(dy)))
+}
+
+// fitOversizeText returns the lines and font size an element is drawn with. Columns with wrap
+// enabled are broken into lines of at most wrap_width characters. Other text wider than the
+// usable timeline width is handled according to timeline.oversize_text:
+//   - "wrap": the text is broken into lines that fit and rendered as stacked tspans
+//   - "truncate": the text is cut short and ends with an ellipsis
+//   - "shrink": the font size is reduced until the text fits (minimum 6px)
+//
+// Any other value, and grid text layouts, leave the text unchanged. fitted reports whether
+// oversize_text changed the text. The bounding box code measures the same result that
+// drawTextElement draws, so collision and edge checks see the fitted text.
+func fitOversizeText(text string, style ColumnStyle, config Config) (lines []string, fontSize int, fitted bool) {
+	fontSize = style.FontSize
+	lines = wrapColumnText(text, style)
+	if gridTextLayout(config) || len(lines) != 1 {
+		return lines, fontSize, false
+	}
+
+	maxWidth := config.Layout.Width - config.Layout.MarginLeft - config.Layout.MarginRight
+	textWidth := estimateTextWidth(text, fontSize)
+	if maxWidth <= 0 || textWidth <= maxWidth {
+		return lines, fontSize, false
+	}
+
+	switch strings.ToLower(config.Timeline.OversizeText) {
+	case "wrap":
+		maxChars := maxInt(int(float64(maxWidth)/(float64(fontSize)*0.6)), 1)
+		return wrapText(strings.Fields(text), maxChars), fontSize, true
+	case "truncate":
+		return []string{truncateTextToWidth(text, fontSize, maxWidth)}, fontSize, true
+	case "shrink":
+		return lines, maxInt(fontSize*maxWidth/textWidth, 6), true
+	}
+	return lines, fontSize, false
+}
+
+// drawTextElement draws a single display element centered at (x, y) using inline styling
+// for maximum flexibility, with the lines and font size from fitOversizeText
+func drawTextElement(svg svgWriter, x, y int, text string, style ColumnStyle, opacity float64, config Config) {
+	lines, fontSize, fitted := fitOversizeText(text, style, config)
+	if fitted {
+		debugPrintf("Text '%s' is wider than the usable width, drawn with oversize_text=%s",
+			debugSnippet(text), config.Timeline.OversizeText)
+	}
+
+	x, anchor := labelAnchor(x, config)
+	writeTextElement(svg, x, y, anchor, lines, fontSize, text, style, opacity)
+}
 
-				// Use inline styling for maximum flexibility
-				fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="middle" font-family="%s" font-size="%d" font-weight="%s" fill="%s">%s</text>`,
-					x, position, style.FontFamily, style.FontSize, style.FontWeight, style.Color, escapeXML(text))
+// drawAnchoredTextElement draws a text element at x with the given SVG text-anchor instead of
+// the timeline.label_placement anchoring, as used for the columns of grid text layouts
+func drawAnchoredTextElement(svg svgWriter, x, y int, anchor, text string, style ColumnStyle, opacity float64, config Config) {
+	writeTextElement(svg, x, y, anchor, wrapColumnText(text, style), style.FontSize, text, style, opacity)
+}
+
+// writeTextElement writes the <text> element for already wrapped lines anchored at x
+func writeTextElement(svg svgWriter, x, y int, anchor string, lines []string, fontSize int, text string, style ColumnStyle, opacity float64) {
+	if globalManifest != nil {
+		bounds := estimateWrappedTextBounds(lines, fontSize)
+		centreX := x
+		switch anchor {
+		case "start":
+			centreX = x + bounds.Width/2
+		case "end":
+			centreX = x - bounds.Width/2
+		}
+		recordManifest(ManifestElement{Type: "text", X: centreX, Y: y, Width: bounds.Width, Height: bounds.Height, Text: text})
+	}
+
+	extraAttrs := opacityAttr(opacity)
+	if style.TextDecoration != "" {
+		extraAttrs += fmt.Sprintf(` text-decoration="%s"`, escapeXML(style.TextDecoration))
+	}
+
+	fmt.Fprintf(svg, `<text x="%d" y="%d" text-anchor="%s" font-family="%s" font-size="%d" font-weight="%s" fill="%s"%s>`,
+		x, y, anchor, style.FontFamily, fontSize, style.FontWeight, style.Color, extraAttrs)
+	if len(lines) == 1 {
+		svg.WriteString(escapeXML(lines[0]))
+	} else {
+		lineHeight := int(float64(fontSize) * 1.2)
+		for i, line := range lines {
+			dy := 0
+			if i > 0 {
+				dy = lineHeight
 			}
+			fmt.Fprintf(svg, `<tspan x="%d" dy="%d">%s</tspan>`, x, dy, escapeXML(line))
 		}
 	}
+	svg.WriteString("</text>")
+}
+
+// labelAnchor returns the text x position and SVG text-anchor for timeline.label_placement.
+// Left and right placements start the text just past the marker so it sits beside the callout.
+func labelAnchor(x int, config Config) (int, string) {
+	offset := config.EventMarker.Size + 4
+	switch strings.ToLower(config.Timeline.LabelPlacement) {
+	case "right":
+		return x + offset, "start"
+	case "left":
+		return x - offset, "end"
+	default:
+		return x, "middle"
+	}
+}
+
+// labelLeftEdge returns the left edge of text of the given width anchored at anchorX by labelAnchor.
+func labelLeftEdge(anchorX, width int, config Config) int {
+	switch strings.ToLower(config.Timeline.LabelPlacement) {
+	case "right":
+		return anchorX
+	case "left":
+		return anchorX - width
+	default:
+		return anchorX - width/2
+	}
+}
+
+// truncateTextToWidth shortens text so that it, plus a trailing ellipsis, fits within maxWidth pixels
+func truncateTextToWidth(text string, fontSize, maxWidth int) string {
+	runes := []rune(text)
+	for len(runes) > 0 && estimateTextWidth(string(runes)+"...", fontSize) > maxWidth {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
 }
 
 // wrapText wraps an array of words into lines that don't exceed maxWidth characters.
@@ -2259,6 +6445,8 @@ func wrapText(words []string, maxWidth int) []string {
 // It replaces XML special characters (&, <, >, ", ') with their corresponding
 // XML entity references (&amp;, &lt;, &gt;, &quot;, &apos;) to prevent
 // malformed XML when the string is embedded in SVG content.
+// Every "&" is escaped, so CSV data that already contains entities renders them literally
+// (e.g. "&amp;amp;") unless columns.unescape_html decodes them while parsing.
 func escapeXML(s string) string {
 	s = strings.ReplaceAll(s, "&", "&amp;")
 	s = strings.ReplaceAll(s, "<", "&lt;")
@@ -2271,11 +6459,15 @@ func escapeXML(s string) string {
 // getOutputFilename determines the output filename for the SVG file.
 // If outputFile is provided and not empty, it returns that filename.
 // Otherwise, it derives the filename from the CSV file by replacing
-// the extension with .svg (e.g., "data.csv" becomes "data.svg").
+// the extension with .svg (e.g., "data.csv" becomes "data.svg"). CSV data read from
+// standard input has no name to derive from and is written to "timeline.svg".
 func getOutputFilename(csvFile, outputFile string) string {
 	if outputFile != "" {
 		return outputFile
 	}
+	if csvFile == stdinCSV {
+		return "timeline.svg"
+	}
 
 	// Use CSV filename with .svg extension
 	base := filepath.Base(csvFile)
@@ -2283,20 +6475,114 @@ func getOutputFilename(csvFile, outputFile string) string {
 	return strings.TrimSuffix(base, ext) + ".svg"
 }
 
+// getCompressedFilename returns the .svgz filename used for gzip-compressed output.
+// A ".svg" extension is replaced, a ".svgz" extension is kept, and any other name
+// simply has ".svgz" appended.
+func getCompressedFilename(outputPath string) string {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".svgz":
+		return outputPath
+	case ".svg":
+		return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".svgz"
+	default:
+		return outputPath + ".svgz"
+	}
+}
+
+// parseFileMode parses an octal permission string such as "0644" or "600" for --file-mode
+func parseFileMode(value string) (os.FileMode, error) {
+	mode, err := strconv.ParseUint(strings.TrimSpace(value), 8, 32)
+	if err != nil || mode > 0777 {
+		return 0, fmt.Errorf("invalid file mode '%s': expected octal permissions such as 0644", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+// writeSVGFile streams the SVG produced by render to outputPath, gzip-compressing it when compress is true.
+// A partially written file is removed when render fails.
+// The file is created with the given permission mode.
+// Compressed output is a standard .svgz file that browsers render natively.
+func writeSVGFile(outputPath string, compress bool, mode os.FileMode, render func(io.Writer) error) error {
+	file, err := os.OpenFile(outputPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	// Apply the mode exactly, regardless of the umask or the mode of an existing file
+	if err := file.Chmod(mode); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if !compress {
+		if err := render(file); err != nil {
+			_ = file.Close()
+			_ = os.Remove(outputPath)
+			return err
+		}
+		return file.Close()
+	}
+
+	gz := gzip.NewWriter(file)
+	gz.Name = strings.TrimSuffix(filepath.Base(outputPath), filepath.Ext(outputPath)) + ".svg"
+	if err := render(gz); err != nil {
+		_ = gz.Close()
+		_ = file.Close()
+		_ = os.Remove(outputPath)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// stdinIsPiped reports whether standard input is a pipe or file rather than a terminal
+func stdinIsPiped() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice == 0
+}
+
 func main() {
 	// Parse command line arguments
 	debugFlag := flag.Bool("debug", false, "Enable debug mode for verbose output")
-	csvFile := flag.String("csv", "", "CSV file with timeline data (required)")
-	configFile := flag.String("config", "", "YAML configuration file (optional)")
+	csvFile := flag.String("csv", "", "CSV file with timeline data, or - for standard input (required unless data is piped in)")
+	configFile := flag.String("config", "", "YAML, JSON, or TOML configuration file (optional)")
 	outputFile := flag.String("output", "", "Output SVG filename (optional)")
+	encoding := flag.String("encoding", "utf-8", "CSV file encoding: utf-8, utf-16, or latin-1")
+	gzipOutput := flag.Bool("gzip-output", false, "Write gzip-compressed SVG (.svgz) output")
+	dedupe := flag.Bool("dedupe", false, "Remove duplicate events, keeping the first occurrence")
+	dedupeKey := flag.String("dedupe-key", "timestamp,title", "Comma-separated columns identifying duplicate events for --dedupe")
+	manifestFile := flag.String("manifest", "", "Write a JSON manifest of drawn markers, lines, and text to this file")
+	fileMode := flag.String("file-mode", "0600", "Octal permissions for the output SVG file, e.g. 0644")
+	showClusters := flag.Bool("show-clusters", false, "Draw a labelled bracket under each detected temporal cluster")
+	debugSVG := flag.Bool("debug-svg", false, "Overlay each event's index, ideal X, final X, and callout length on the SVG")
+	lazyQuotes := flag.Bool("lazy-quotes", false, "Tolerate stray and unescaped quotes in CSV fields")
+	var overrides configOverrides
+	flag.Var(&overrides, "set", "Override a config value, e.g. timeline.min_text_spacing=20 (repeatable)")
+	highlight := flag.String("highlight", "", "Comma-separated indices (0-based, chronological) of events to highlight")
+	var filters eventFilters
+	flag.Var(&filters, "filter", "Only render events where column=value or column!=value (repeatable, case-insensitive)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
 		fmt.Fprintf(os.Stderr, "  --debug             Enable debug mode for verbose output\n")
-		fmt.Fprintf(os.Stderr, "  --csv <file>        CSV file with timeline data (required)\n")
-		fmt.Fprintf(os.Stderr, "  --config <file>     YAML configuration file (optional)\n")
+		fmt.Fprintf(os.Stderr, "  --csv <file>        CSV file with timeline data, or - to read standard input (required unless piped)\n")
+		fmt.Fprintf(os.Stderr, "  --config <file>     YAML, JSON, or TOML configuration file (optional)\n")
 		fmt.Fprintf(os.Stderr, "  --output <file>     Output SVG filename (optional)\n")
+		fmt.Fprintf(os.Stderr, "  --encoding <name>   CSV file encoding: utf-8, utf-16, latin-1 (default utf-8)\n")
+		fmt.Fprintf(os.Stderr, "  --lazy-quotes       Tolerate stray and unescaped quotes in CSV fields instead of failing\n")
+		fmt.Fprintf(os.Stderr, "  --gzip-output       Write gzip-compressed SVG with a .svgz extension\n")
+		fmt.Fprintf(os.Stderr, "  --dedupe            Remove duplicate events, keeping the first occurrence\n")
+		fmt.Fprintf(os.Stderr, "  --dedupe-key <cols> Comma-separated columns that identify duplicates (default timestamp,title)\n")
+		fmt.Fprintf(os.Stderr, "  --manifest <file>   Write a JSON manifest of drawn markers, lines, and text\n")
+		fmt.Fprintf(os.Stderr, "  --file-mode <mode>  Octal permissions for the output file (default 0600)\n")
+		fmt.Fprintf(os.Stderr, "  --debug-svg         Overlay each event's index, ideal X, final X, and callout length\n")
+		fmt.Fprintf(os.Stderr, "  --show-clusters     Draw a bracket under each detected temporal cluster with its size and span\n")
+		fmt.Fprintf(os.Stderr, "  --set <path=value>  Override a config value, e.g. layout.width=1600 (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  --highlight <list>  Highlight events by 0-based chronological index, e.g. 0,4\n")
+		fmt.Fprintf(os.Stderr, "  --filter <col=val>  Only render events where a column equals (=) or differs from (!=) a value (repeatable)\n")
 		fmt.Fprintf(os.Stderr, "\nThe CSV file should have columns for timestamp and other data.\n")
 		fmt.Fprintf(os.Stderr, "If no config file is specified, default settings will be used.\n")
 		fmt.Fprintf(os.Stderr, "If no output file is specified, the CSV filename with .svg extension will be used.\n")
@@ -2324,60 +6610,131 @@ func main() {
 		_ = absTimeDuration
 		_ = wrapText
 		_ = estimateWrappedTextBounds
+		_ = generateSVG
 	}
 
-	// Validate required arguments
+	// Validate required arguments; piped input stands in for a missing --csv
+	if *csvFile == "" && stdinIsPiped() {
+		*csvFile = stdinCSV
+	}
 	if *csvFile == "" {
 		fmt.Fprintf(os.Stderr, "Error: CSV file is required. Use --csv to specify the file.\n\n")
 		flag.Usage()
 		os.Exit(1)
 	}
 
+	outputMode, err := parseFileMode(*fileMode)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Load configuration
 	config, err := loadConfig(*configFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
+	if err := applyConfigOverrides(&config, overrides); err != nil {
+		fmt.Fprintf(os.Stderr, "Error applying configuration overrides: %v\n", err)
+		os.Exit(1)
+	}
+	if *showClusters {
+		config.Timeline.ShowClusters = true
+	}
+	if *debugSVG {
+		config.Timeline.DebugLayer = true
+	}
+	if *lazyQuotes {
+		config.Columns.LazyQuotes = true
+	}
+	if err := validateConfig(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error in configuration: %v\n", err)
+		os.Exit(1)
+	}
+	applyFontScale(&config)
 	debugPrintf("Configuration loaded. Font size: %d, Show dates: %t", config.Font.Size, config.Timeline.ShowDates)
 
-	// Parse CSV file
-	events, err := parseCSV(*csvFile, config)
+	// Parse CSV file, or standard input for "-"
+	csvSource := *csvFile
+	var events []TimelineEvent
+	if *csvFile == stdinCSV {
+		csvSource = "standard input"
+		events, err = parseCSVFrom(os.Stdin, *encoding, config)
+	} else {
+		events, err = parseCSV(*csvFile, *encoding, config)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing CSV file: %v\n", err)
 		os.Exit(1)
 	}
-	debugPrintf("Parsed %d events from %s", len(events), *csvFile)
+	debugPrintf("Parsed %d events from %s", len(events), csvSource)
+
+	if len(filters) > 0 {
+		before := len(events)
+		events, err = filterEvents(events, filters)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		debugPrintf("Filters %v kept %d of %d events", []string(filters), len(events), before)
+	}
+
+	if *dedupe {
+		var removed int
+		events, removed = dedupeEvents(events, strings.Split(*dedupeKey, ","), config)
+		fmt.Fprintf(os.Stderr, "Removed %d duplicate events\n", removed)
+	}
 
 	if len(events) == 0 {
 		fmt.Fprintf(os.Stderr, "Error: No events found in CSV file\n")
 		os.Exit(1)
 	}
 
-	fmt.Printf("Loaded %d events from %s\n", len(events), *csvFile)
+	fmt.Printf("Loaded %d events from %s\n", len(events), csvSource)
 
-	// Generate SVG
-	svgContent := generateSVG(events, config)
-	if svgContent == "" {
-		fmt.Fprintf(os.Stderr, "Error: Failed to generate SVG content\n")
-		os.Exit(1)
+	if *highlight != "" {
+		if err := highlightEvents(events, *highlight); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
 	// Determine output filename
 	outputPath := getOutputFilename(*csvFile, *outputFile)
+	if *gzipOutput {
+		outputPath = getCompressedFilename(outputPath)
+	}
 
-	// Write SVG file
-	err = os.WriteFile(outputPath, []byte(svgContent), 0600)
+	if *manifestFile != "" {
+		globalManifest = &LayoutManifest{}
+	}
+
+	// Linked assets are written relative to where the SVG ends up
+	config.OutputDir = filepath.Dir(outputPath)
+
+	// Generate the SVG straight into the output file
+	err = writeSVGFile(outputPath, *gzipOutput, outputMode, func(w io.Writer) error {
+		return GenerateSVGTo(w, events, config)
+	})
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing SVG file: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error generating SVG file: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Printf("Timeline SVG generated successfully: %s\n", outputPath)
+
+	if globalManifest != nil {
+		if err := writeManifestFile(*manifestFile, globalManifest); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing manifest file: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Layout manifest written: %s\n", *manifestFile)
+	}
 }
 
 // calculateCalloutLength determines the optimal callout line length for collision avoidance with boundary constraints
-func calculateCalloutLength(x, index int, allPositions []int, above bool, config Config, timelineY int) int {
+func calculateCalloutLength(x, index int, allPositions []int, above bool, config Config, timelineY, textHeight int) int {
 	if !config.Timeline.AvoidTextOverlap {
 		return config.Timeline.MinCalloutLength
 	}
@@ -2389,8 +6746,7 @@ func calculateCalloutLength(x, index int, allPositions []int, above bool, config
 	}{}
 
 	for i, pos := range allPositions {
-		eventAbove := i%2 == 0
-		if eventAbove == above {
+		if eventAbove(i, config) == above {
 			sameHeightEvents = append(sameHeightEvents, struct {
 				index int
 				x     int
@@ -2398,8 +6754,8 @@ func calculateCalloutLength(x, index int, allPositions []int, above bool, config
 		}
 	}
 
-	// Sort by x position for easier collision detection
-	sort.Slice(sameHeightEvents, func(i, j int) bool {
+	// Sort by x position for easier collision detection; events sharing an x stay in event order
+	sort.SliceStable(sameHeightEvents, func(i, j int) bool {
 		return sameHeightEvents[i].x < sameHeightEvents[j].x
 	})
 
@@ -2503,7 +6859,8 @@ func calculateCalloutLength(x, index int, allPositions []int, above bool, config
 	}
 
 	// Apply boundary constraints to prevent text overflow
-	maxSafeCallout := calculateMaxSafeCallout(timelineY, above, config)
+	arcY, _ := arcPoint(x, timelineY, config)
+	maxSafeCallout := calculateMaxSafeCallout(arcY, above, textHeight, config)
 	if baseLength > maxSafeCallout {
 		baseLength = maxSafeCallout
 	}
@@ -2514,45 +6871,164 @@ func calculateCalloutLength(x, index int, allPositions []int, above bool, config
 
 // calculateMaxSafeCallout determines the maximum safe callout length to prevent text overflow.
 // It calculates the available vertical space between the timeline and the SVG boundaries,
-// taking into account the estimated text height for title, date, and notes elements.
-// For above-timeline events, it ensures text doesn't exceed the top margin.
-// For below-timeline events, it ensures text doesn't exceed the bottom margin.
+// minus the space reserved for the event text on that side: layout.text_reserve_top or
+// layout.text_reserve_bottom when set, otherwise the measured textHeight plus a small buffer.
+// Events with above=true grow downwards in SVG coordinates, so they are limited by the
+// bottom margin; the others are limited by the top margin.
 // Returns a callout length that keeps all text within the SVG bounds.
-func calculateMaxSafeCallout(timelineY int, above bool, config Config) int {
-	// Estimate text height based on configuration
-	// Title: font size + 2 (bold), Date: font size - 1, Notes: estimated 4 lines max of font size - 2
-	titleHeight := config.Font.Size + 2 + 5 // +5 for spacing
-	dateHeight := 0
-	if config.Timeline.ShowDates {
-		dateHeight = config.Font.Size - 1 + 5 // +5 for spacing
-	}
-	notesHeight := (config.Font.Size-2)*4 + (3 * 4) // 4 lines max with 3px spacing between
-
-	estimatedTextHeight := titleHeight + dateHeight + notesHeight + 20 // +20 buffer
+func calculateMaxSafeCallout(timelineY int, above bool, textHeight int, config Config) int {
+	reserve := textHeight + 20 // +20 buffer
 
+	var availableSpace int
 	if above {
-		// For above timeline, ensure text doesn't go beyond top margin
-		availableSpace := timelineY - config.Layout.MarginTop
-		maxCallout := availableSpace - estimatedTextHeight
-		if maxCallout < config.Timeline.MinCalloutLength {
-			maxCallout = config.Timeline.MinCalloutLength
+		// Text stacks downwards, so ensure it doesn't go beyond the bottom margin
+		availableSpace = config.Layout.Height - config.Layout.MarginBottom - timelineY
+		if config.Layout.TextReserveBottom > 0 {
+			reserve = config.Layout.TextReserveBottom
 		}
-		return maxCallout
 	} else {
-		// For below timeline, ensure text doesn't go beyond bottom margin
-		svgBottom := config.Layout.Height - config.Layout.MarginBottom
-		availableSpace := svgBottom - timelineY
-		maxCallout := availableSpace - estimatedTextHeight
-		if maxCallout < config.Timeline.MinCalloutLength {
-			maxCallout = config.Timeline.MinCalloutLength
+		// Text stacks upwards, so ensure it doesn't go beyond the top margin
+		availableSpace = timelineY - config.Layout.MarginTop
+		if config.Layout.TextReserveTop > 0 {
+			reserve = config.Layout.TextReserveTop
+		}
+	}
+
+	maxCallout := availableSpace - reserve
+	if maxCallout < config.Timeline.MinCalloutLength {
+		maxCallout = config.Timeline.MinCalloutLength
+	}
+	return maxCallout
+}
+
+// measureEventTextHeight returns the stacked height of an event's displayed text elements,
+// including wrapped lines and the padding between elements. It is measured per event so
+// single-line events are not limited by the space a taller neighbour would need.
+func measureEventTextHeight(event TimelineEvent, config Config) int {
+	height := 0
+	for _, row := range textRows(event, config) {
+		rowHeight, extra := textRowSize(event, row, config)
+		height += rowHeight + config.Timeline.TextElementPadding + extra
+	}
+	return height
+}
+
+// eventIconImage returns the icons image for the event's columns.icon_column value, or ""
+// when the event has no icon value or no icon matches it
+func eventIconImage(event TimelineEvent, config Config) string {
+	if config.Columns.IconColumn == "" {
+		return ""
+	}
+	value := strings.TrimSpace(event.Data[strings.ToLower(config.Columns.IconColumn)])
+	if value == "" {
+		return ""
+	}
+	for _, icon := range config.Icons {
+		if strings.EqualFold(strings.TrimSpace(icon.Value), value) {
+			return icon.Image
+		}
+	}
+	return ""
+}
+
+// eventMarkerShape returns the marker shape from the event's columns.shape_column value, or
+// event_marker.shape when the event has no value or names an unknown shape
+func eventMarkerShape(event TimelineEvent, config Config) string {
+	if config.Columns.ShapeColumn == "" {
+		return config.EventMarker.Shape
+	}
+	value := strings.ToLower(strings.TrimSpace(event.Data[strings.ToLower(config.Columns.ShapeColumn)]))
+	switch value {
+	case "circle", "triangle", "square", "diamond":
+		return value
+	}
+	if value != "" {
+		debugPrintf("Ignoring unknown marker shape '%s' in column '%s'", value, config.Columns.ShapeColumn)
+	}
+	return config.EventMarker.Shape
+}
+
+// isLinkedImage reports whether an icon image is a URL or data URI rather than a local file
+func isLinkedImage(image string) bool {
+	lower := strings.ToLower(image)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "data:")
+}
+
+// linkEventIcons returns a copy of the icons list with each relative image file rewritten so
+// that it still points at the file next to the config when the SVG viewer resolves it against
+// the SVG's own location: relative to OutputDir when known, otherwise relative to the config
+// file's directory as seen from the working directory. URLs, data URIs, and absolute paths are
+// kept as they are.
+func linkEventIcons(config Config) []EventIcon {
+	linked := make([]EventIcon, len(config.Icons))
+	for i, icon := range config.Icons {
+		linked[i] = icon
+		if icon.Image == "" || isLinkedImage(icon.Image) || filepath.IsAbs(icon.Image) {
+			continue
+		}
+		path := resolveConfigPath(config, icon.Image)
+		if config.OutputDir != "" {
+			target, errTarget := filepath.Abs(path)
+			base, errBase := filepath.Abs(config.OutputDir)
+			if errTarget == nil && errBase == nil {
+				if rel, err := filepath.Rel(base, target); err == nil {
+					path = rel
+				}
+			}
+		}
+		linked[i].Image = filepath.ToSlash(path)
+	}
+	return linked
+}
+
+// embedEventIcons returns a copy of the icons list with each local image file replaced by a
+// base64 data URI, so the SVG no longer depends on the files next to it. Relative files are
+// read from the config file's directory; URLs and data URIs are kept as they are.
+func embedEventIcons(config Config) ([]EventIcon, error) {
+	embedded := make([]EventIcon, len(config.Icons))
+	for i, icon := range config.Icons {
+		embedded[i] = icon
+		if icon.Image == "" || isLinkedImage(icon.Image) {
+			continue
+		}
+		data, err := os.ReadFile(resolveConfigPath(config, icon.Image))
+		if err != nil {
+			return nil, fmt.Errorf("failed to embed icons[%d].image: %w", i, err)
+		}
+		mimeType := mime.TypeByExtension(strings.ToLower(filepath.Ext(icon.Image)))
+		if mimeType == "" {
+			mimeType = http.DetectContentType(data)
 		}
-		return maxCallout
+		embedded[i].Image = "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	}
+	return embedded, nil
+}
+
+// drawEventMarkerOrIcon draws the event's icon centred on the marker position when
+// columns.icon_column picks one from the icons list, and the configured marker otherwise
+func drawEventMarkerOrIcon(svg svgWriter, event TimelineEvent, x, y int, config Config, opacity float64) {
+	image := eventIconImage(event, config)
+	if image == "" {
+		config.EventMarker.Shape = eventMarkerShape(event, config)
+		drawEventMarker(svg, x, y, config, opacity, event.Highlighted)
+		return
+	}
+
+	size := config.EventMarker.IconSize
+	if size <= 0 {
+		size = config.EventMarker.Size * 2
+	}
+	if event.Highlighted {
+		size = size * 3 / 2
 	}
+	recordManifest(ManifestElement{Type: "marker", X: x, Y: y, Width: size, Height: size})
+	fmt.Fprintf(svg, `<image href="%s" x="%d" y="%d" width="%d" height="%d" preserveAspectRatio="xMidYMid meet"%s/>`,
+		escapeXML(image), x-size/2, y-size/2, size, size, opacityAttr(opacity))
 }
 
 // drawEventMarker draws the appropriate marker shape at the specified position on the timeline.
 // It supports multiple marker shapes (circle, square, diamond, triangle) with configurable
-// size, fill color, stroke color, and stroke width. The marker is rendered as SVG elements
+// size, fill color, stroke color, stroke width, and stroke dash pattern. The marker is rendered as SVG elements
 // and appended to the provided string builder.
 //
 // Supported shapes:
@@ -2561,44 +7037,96 @@ func calculateMaxSafeCallout(timelineY int, above bool, config Config) int {
 //   - "diamond": Diamond-shaped marker created using a rotated square polygon
 //   - "triangle": Upward-pointing triangular marker
 //   - Default: Falls back to circle for unknown shapes
-func drawEventMarker(svg *strings.Builder, x, y int, config Config) {
+//
+// The event_marker.style option changes how the shape is filled:
+//   - "ring": the shape is drawn hollow, outlined in the fill color with a border twice
+//     stroke_width (at least a quarter of size) and filled with the background color
+//   - "target": a ring with a centre dot in the fill color, a third of size in radius
+//   - "solid" or empty: the shape is filled as configured
+//
+// The opacity is applied to the whole marker; a value of 1.0 leaves the marker fully opaque.
+// Highlighted markers are drawn half again as large with a bolder border, inside a ring in
+// colors.highlight.
+func drawEventMarker(svg svgWriter, x, y int, config Config, opacity float64, highlighted bool) {
+	// Highlighted events get a larger marker with a bolder border, drawn inside a colored ring
+	if highlighted {
+		config.EventMarker.Size = config.EventMarker.Size * 3 / 2
+		config.EventMarker.StrokeWidth = maxInt(config.EventMarker.StrokeWidth*2, 2)
+		ringColor := config.Colors.Highlight
+		if ringColor == "" {
+			ringColor = "#e53935"
+		}
+		fmt.Fprintf(svg, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="%s" stroke-width="3"%s/>`,
+			x, y, config.EventMarker.Size+5, ringColor, opacityAttr(opacity))
+	}
+
 	size := config.EventMarker.Size
 	fillColor := config.EventMarker.FillColor
 	strokeColor := config.EventMarker.StrokeColor
 	strokeWidth := config.EventMarker.StrokeWidth
+	extraAttrs := opacityAttr(opacity)
+
+	recordManifest(ManifestElement{Type: "marker", X: x, Y: y, Width: size * 2, Height: size * 2})
+
+	// Outline-only markers; an empty fill color would otherwise emit an invalid fill attribute
+	noFill := strings.EqualFold(config.EventMarker.Fill, "none") || fillColor == ""
+	dotColor := fillColor
+	if noFill {
+		fillColor = "none"
+		dotColor = strokeColor
+	}
+
+	markerStyle := strings.ToLower(config.EventMarker.Style)
+	if markerStyle == "ring" || markerStyle == "target" {
+		// Hollow markers keep the timeline from showing through by filling with the background
+		if !noFill {
+			strokeColor = fillColor
+			fillColor = config.Colors.Background
+		}
+		strokeWidth = maxInt(strokeWidth*2, maxInt(size/4, 1))
+	}
+	if config.EventMarker.StrokeDash != "" {
+		extraAttrs += fmt.Sprintf(` stroke-dasharray="%s"`, escapeXML(config.EventMarker.StrokeDash))
+	}
 
 	switch strings.ToLower(config.EventMarker.Shape) {
 	case "circle":
-		fmt.Fprintf(svg, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="%s" stroke-width="%d"/>`,
-			x, y, size, fillColor, strokeColor, strokeWidth)
+		fmt.Fprintf(svg, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="%s" stroke-width="%d"%s/>`,
+			x, y, size, fillColor, strokeColor, strokeWidth, extraAttrs)
 
 	case "square":
 		halfSize := size
-		fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s" stroke-width="%d"/>`,
-			x-halfSize, y-halfSize, size*2, size*2, fillColor, strokeColor, strokeWidth)
+		fmt.Fprintf(svg, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s" stroke="%s" stroke-width="%d"%s/>`,
+			x-halfSize, y-halfSize, size*2, size*2, fillColor, strokeColor, strokeWidth, extraAttrs)
 
 	case "diamond":
 		// Draw diamond as a rotated square using polygon
-		fmt.Fprintf(svg, `<polygon points="%d,%d %d,%d %d,%d %d,%d" fill="%s" stroke="%s" stroke-width="%d"/>`,
+		fmt.Fprintf(svg, `<polygon points="%d,%d %d,%d %d,%d %d,%d" fill="%s" stroke="%s" stroke-width="%d"%s/>`,
 			x, y-size, // top
 			x+size, y, // right
 			x, y+size, // bottom
 			x-size, y, // left
-			fillColor, strokeColor, strokeWidth)
+			fillColor, strokeColor, strokeWidth, extraAttrs)
 
 	case "triangle":
 		// Draw upward pointing triangle
 		height := int(float64(size) * 1.5) // Make triangle a bit taller for better visibility
-		fmt.Fprintf(svg, `<polygon points="%d,%d %d,%d %d,%d" fill="%s" stroke="%s" stroke-width="%d"/>`,
+		fmt.Fprintf(svg, `<polygon points="%d,%d %d,%d %d,%d" fill="%s" stroke="%s" stroke-width="%d"%s/>`,
 			x, y-height, // top point
 			x-size, y+height/2, // bottom left
 			x+size, y+height/2, // bottom right
-			fillColor, strokeColor, strokeWidth)
+			fillColor, strokeColor, strokeWidth, extraAttrs)
 
 	default:
 		// Default to circle if unknown shape
-		fmt.Fprintf(svg, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="%s" stroke-width="%d"/>`,
-			x, y, size, fillColor, strokeColor, strokeWidth)
+		fmt.Fprintf(svg, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="%s" stroke-width="%d"%s/>`,
+			x, y, size, fillColor, strokeColor, strokeWidth, extraAttrs)
+	}
+
+	// Target markers add a centre dot inside the ring
+	if markerStyle == "target" {
+		fmt.Fprintf(svg, `<circle cx="%d" cy="%d" r="%d" fill="%s"%s/>`,
+			x, y, maxInt(size/3, 1), dotColor, opacityAttr(opacity))
 	}
 }
 
@@ -0,0 +1,821 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testEvent returns an event at the given time with the given title and notes
+func testEvent(timestamp, title, notes string) TimelineEvent {
+	parsed, err := time.Parse("2006-01-02 15:04", timestamp)
+	if err != nil {
+		panic(err)
+	}
+	return TimelineEvent{
+		Timestamp: parsed,
+		Data:      map[string]string{"title": title, "notes": notes},
+	}
+}
+
+func TestOversizeTextIsMeasuredAsDrawn(t *testing.T) {
+	config := getDefaultConfig()
+	config.Layout.Width = 400
+	usable := config.Layout.Width - config.Layout.MarginLeft - config.Layout.MarginRight
+	event := testEvent("2024-01-01 00:00", strings.Repeat("overlong title ", 3), "")
+	unfittedHeight := calculateEventBoundingBox(event, 200, 400, 40, 0, config).Height
+
+	tests := []struct {
+		mode       string
+		fits       bool
+		taller     bool
+		fontShrunk bool
+	}{
+		{mode: "", fits: false},
+		{mode: "wrap", fits: true, taller: true},
+		{mode: "truncate", fits: true},
+		{mode: "shrink", fits: true, fontShrunk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			config := config
+			config.Timeline.OversizeText = tt.mode
+
+			width := textBlockWidth(event, config)
+			if fits := width <= usable; fits != tt.fits {
+				t.Errorf("textBlockWidth = %d with usable width %d, want fits=%t", width, usable, tt.fits)
+			}
+
+			box := calculateEventBoundingBox(event, 200, 400, 40, 0, config)
+			if box.Width != width+10 {
+				t.Errorf("bounding box width = %d, want text width %d plus padding", box.Width, width)
+			}
+			if taller := box.Height > unfittedHeight; taller != tt.taller {
+				t.Errorf("bounding box height = %d (unfitted %d), want taller=%t", box.Height, unfittedHeight, tt.taller)
+			}
+
+			_, fontSize, _ := fitOversizeText(getElementText(event, "title", config), getColumnStyle("title", config), config)
+			if shrunk := fontSize < config.Font.Size; shrunk != tt.fontShrunk {
+				t.Errorf("fitted font size = %d (configured %d), want shrunk=%t", fontSize, config.Font.Size, tt.fontShrunk)
+			}
+		})
+	}
+}
+
+func TestResolve2DCollisionsKeepsPinnedCallouts(t *testing.T) {
+	config := getDefaultConfig()
+	config.Timeline.MaxCollisionIterations = 50
+	events := []TimelineEvent{
+		testEvent("2024-01-01 00:00", "First event", ""),
+		testEvent("2024-01-01 00:10", "Second event", ""),
+		testEvent("2024-01-01 00:20", "Third event", ""),
+		testEvent("2024-01-01 00:30", "Fourth event", ""),
+	}
+	timelineY := timelineAxisY(config)
+	positions := []int{400, 420, 440, 460}
+	callouts := []int{40, 40, 40, 40}
+
+	for _, pinned := range [][]int{{0, 0, 60, 0}, {0, 0, 60, 60}} {
+		resolvedPositions, resolved := resolve2DCollisions(events, positions, callouts, pinned, nil, nil, timelineY, config)
+		for i, length := range pinned {
+			if length > 0 && resolved[i] != length {
+				t.Errorf("pins %v: callout %d changed to %d, want %d", pinned, i, resolved[i], length)
+			}
+		}
+		for i := range events {
+			for j := i + 1; j < len(events); j++ {
+				boxI := calculateEventBoundingBox(events[i], resolvedPositions[i], timelineY, resolved[i], i, config)
+				boxJ := calculateEventBoundingBox(events[j], resolvedPositions[j], timelineY, resolved[j], j, config)
+				if (pinned[i] > 0 || pinned[j] > 0) && detectBoundingBoxOverlap(boxI, boxJ) {
+					t.Errorf("pins %v: pinned event text %d and %d still overlap", pinned, i, j)
+				}
+			}
+		}
+	}
+}
+
+func TestShortCalloutsKeepTextClearOfTheLine(t *testing.T) {
+	config := getDefaultConfig()
+	config.Timeline.MinCalloutLength = 2
+	config.Timeline.MaxCalloutLength = 4
+	config.Timeline.CalloutLevels = 1
+	config.Timeline.LayoutAlgorithm = "equal_spacing"
+	events := []TimelineEvent{
+		testEvent("2024-01-01 00:00", "Kickoff", "Project starts"),
+		testEvent("2024-02-01 00:00", "Design", "Review done"),
+		testEvent("2024-03-01 00:00", "Build", "First release"),
+		testEvent("2024-04-01 00:00", "Launch", "Live"),
+	}
+	timelineY := timelineAxisY(config)
+	halfLine := (config.Timeline.LineWidth + 1) / 2
+
+	floors := baselineCalloutFloors(events, timelineY, config)
+	for i, floor := range floors {
+		// Text drawn below the line starts at the callout end, so a 2px callout puts it on the line
+		if eventAbove(i, config) && floor <= config.Timeline.MinCalloutLength {
+			t.Errorf("event %d: floor %d does not lengthen a %dpx callout", i, floor, config.Timeline.MinCalloutLength)
+		}
+	}
+
+	positions := calculateEqualSpacingPositions(events, 100, 1000, config)
+	callouts := globalOptimizedCallouts
+	for i, event := range events {
+		box := calculateEventBoundingBox(event, positions[i], timelineY, callouts[i], i, config)
+		// above=true draws the text below the line
+		if box.Above && box.Top < timelineY+halfLine || !box.Above && box.Bottom > timelineY-halfLine {
+			t.Errorf("event %d: text box %d..%d overlaps the line at y=%d (callout %d)", i, box.Top, box.Bottom, timelineY, callouts[i])
+		}
+	}
+}
+
+func TestParseCSVFromStripsHeaderBOM(t *testing.T) {
+	input := "\ufefftimestamp,title\n2024-01-02 10:00,Second\n2024-01-01 09:00,First\n"
+	events, err := parseCSVFrom(strings.NewReader(input), "utf-8", getDefaultConfig())
+	if err != nil {
+		t.Fatalf("parseCSVFrom: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if got := events[0].Data["title"]; got != "First" {
+		t.Errorf("first event title = %q, want %q", got, "First")
+	}
+	if _, ok := events[0].Data["\ufefftimestamp"]; ok {
+		t.Errorf("BOM left on the first header: %v", events[0].Data)
+	}
+}
+
+func TestStyledColumnsStackByTheirOwnHeights(t *testing.T) {
+	config := getDefaultConfig()
+	config.Columns.UseDetailedStyling = true
+	config.Columns.DetailedColumns = []ColumnStyle{
+		{Name: "title", FontSize: 16},
+		{Name: SubtitleColumn, FontSize: 10},
+		{Name: "owner", FontSize: 9},
+		{Name: "notes", FontSize: 8},
+	}
+	event := testEvent("2024-01-01 00:00", "Title", "Notes")
+	event.Data[SubtitleColumn] = "Subtitle"
+	event.Data["owner"] = "Owner"
+
+	padding := config.Timeline.TextElementPadding
+	height := func(fontSize int) int { return estimateTextBounds("x", fontSize).Height }
+	const eventY = 300
+
+	below := calculateConfigurableTextPositions(event, eventY, true, config)
+	wantBelow := map[string]int{
+		"title":        eventY,
+		SubtitleColumn: eventY + height(10) + padding,
+		"owner":        eventY + height(10) + height(9) + 2*padding,
+		"notes":        eventY + height(10) + height(9) + height(8) + 3*padding,
+	}
+	above := calculateConfigurableTextPositions(event, eventY, false, config)
+	wantAbove := map[string]int{
+		"title":        eventY,
+		SubtitleColumn: eventY - height(16) - padding,
+		"owner":        eventY - height(16) - height(10) - 2*padding,
+		"notes":        eventY - height(16) - height(10) - height(9) - 3*padding,
+	}
+	for name, want := range wantBelow {
+		if below[name] != want {
+			t.Errorf("below the line: %s at y=%d, want %d", name, below[name], want)
+		}
+	}
+	for name, want := range wantAbove {
+		if above[name] != want {
+			t.Errorf("above the line: %s at y=%d, want %d", name, above[name], want)
+		}
+	}
+}
+
+func TestSubtitleIsNotDisplayedByDefault(t *testing.T) {
+	for _, name := range getColumnOrder(getDefaultConfig()) {
+		if name == SubtitleColumn {
+			t.Fatalf("default column order %v includes %q", getColumnOrder(getDefaultConfig()), SubtitleColumn)
+		}
+	}
+}
+
+func TestParseCSVFromRaggedRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "short row",
+			input: "title,timestamp,notes\nShort,2024-01-01 10:00\nFull,2024-01-02 10:00,Has notes\n",
+		},
+		{
+			name:    "missing timestamp field",
+			input:   "title,timestamp,notes\nFull,2024-01-02 10:00,Has notes\nOnly a title\n",
+			wantErr: "error parsing CSV row: row has 1 fields and is missing the timestamp column",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := parseCSVFrom(strings.NewReader(tt.input), "utf-8", getDefaultConfig())
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCSVFrom: %v", err)
+			}
+			if len(events) != 2 {
+				t.Fatalf("got %d events, want 2", len(events))
+			}
+			notes, ok := events[0].Data["notes"]
+			if events[0].Data["title"] != "Short" || !ok || notes != "" {
+				t.Errorf("short row data = %v, want title Short and empty notes", events[0].Data)
+			}
+		})
+	}
+}
+
+func TestUnescapeHTMLRendersEntitiesOnce(t *testing.T) {
+	config := getDefaultConfig()
+	config.Columns.UnescapeHTML = true
+	input := "timestamp,title\n2024-01-01 10:00,Tom &amp; Jerry\n2024-01-02 10:00,Salt &lt;fine&gt;\n"
+	events, err := parseCSVFrom(strings.NewReader(input), "utf-8", config)
+	if err != nil {
+		t.Fatalf("parseCSVFrom: %v", err)
+	}
+	if got := events[0].Data["title"]; got != "Tom & Jerry" {
+		t.Errorf("parsed title = %q, want %q", got, "Tom & Jerry")
+	}
+
+	var svg bytes.Buffer
+	if err := GenerateSVGTo(&svg, events, config); err != nil {
+		t.Fatalf("GenerateSVGTo: %v", err)
+	}
+	out := svg.String()
+	for _, want := range []string{">Tom &amp; Jerry<", ">Salt &lt;fine&gt;<"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "&amp;amp;") || strings.Contains(out, "&amp;lt;") {
+		t.Errorf("SVG contains double-escaped entities")
+	}
+}
+
+// burstEvents returns count events five minutes apart starting at each of the given times
+func burstEvents(count int, starts ...string) []TimelineEvent {
+	var events []TimelineEvent
+	for _, start := range starts {
+		first := testEvent(start, "", "").Timestamp
+		for i := 0; i < count; i++ {
+			at := first.Add(time.Duration(i) * 5 * time.Minute)
+			events = append(events, TimelineEvent{Timestamp: at, Data: map[string]string{"title": "Burst " + at.Format("15:04")}})
+		}
+	}
+	return events
+}
+
+func TestDetectClustersFindsEveryBurst(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []TimelineEvent
+		want   [][]int
+	}{
+		{
+			name:   "two clusters",
+			events: burstEvents(3, "2024-01-01 08:00", "2024-01-01 14:00"),
+			want:   [][]int{{0, 1, 2}, {3, 4, 5}},
+		},
+		{
+			name:   "three clusters",
+			events: burstEvents(3, "2024-01-01 08:00", "2024-01-01 14:00", "2024-01-01 20:00"),
+			want:   [][]int{{0, 1, 2}, {3, 4, 5}, {6, 7, 8}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := getDefaultConfig()
+			config.Timeline.MinTextSpacing = 10
+			clusters := detectClusters(tt.events, clusterThreshold(config))
+			if len(clusters) != len(tt.want) {
+				t.Fatalf("detectClusters = %v, want %v", clusters, tt.want)
+			}
+			for c, cluster := range clusters {
+				if len(cluster) != len(tt.want[c]) {
+					t.Fatalf("detectClusters = %v, want %v", clusters, tt.want)
+				}
+				for k, i := range cluster {
+					if i != tt.want[c][k] {
+						t.Fatalf("detectClusters = %v, want %v", clusters, tt.want)
+					}
+				}
+			}
+
+			membership := clusterMembership(clusters, len(tt.events))
+			for c, cluster := range tt.want {
+				for _, i := range cluster {
+					if membership[i] != c {
+						t.Errorf("clusterMembership[%d] = %d, want %d", i, membership[i], c)
+					}
+				}
+			}
+
+			// Each burst stays tightly grouped: narrower than the gap to the next burst
+			positions := calculateSmartPositions(tt.events, 100, 1000, config.Timeline.MinTextSpacing, config)
+			for c := 0; c+1 < len(tt.want); c++ {
+				cluster, next := tt.want[c], tt.want[c+1]
+				span := positions[cluster[len(cluster)-1]] - positions[cluster[0]]
+				gap := positions[next[0]] - positions[cluster[len(cluster)-1]]
+				if span >= gap {
+					t.Errorf("cluster %d spans %dpx but is only %dpx from the next cluster (positions %v)", c, span, gap, positions)
+				}
+			}
+		})
+	}
+}
+
+func TestProportionalXRoundsEvenlySpacedEvents(t *testing.T) {
+	const startX, width, count = 100, 1000, 7
+	first := testEvent("2024-01-01 00:00", "", "").Timestamp
+	last := first.Add((count - 1) * time.Hour)
+
+	totalBias := 0.0
+	for i := 0; i < count; i++ {
+		at := first.Add(time.Duration(i) * time.Hour)
+		proportion := float64(at.Sub(first)) / float64(last.Sub(first))
+		exact := float64(startX) + proportion*width
+		x := proportionalX(startX, width, proportion)
+
+		if diff := float64(x) - exact; diff < -0.5 || diff > 0.5 {
+			t.Errorf("event %d at x=%d, more than half a pixel from %.2f", i, x, exact)
+		}
+		totalBias += float64(x) - exact
+		if i == count-1 && x != startX+width {
+			t.Errorf("last event at x=%d, want exactly %d", x, startX+width)
+		}
+	}
+	// Truncation would put every event up to a pixel to the left; rounding balances out
+	if totalBias <= -1 || totalBias >= 1 {
+		t.Errorf("total rounding bias %.2fpx, want under 1px either way", totalBias)
+	}
+}
+
+func TestFitPositionsToBoundsCompressesOversizedSets(t *testing.T) {
+	const startX, width = 100, 500
+	tests := []struct {
+		name      string
+		positions []int
+	}{
+		{name: "wider than usable width", positions: []int{50, 150, 300, 600, 1400}},
+		{name: "past the right edge", positions: []int{500, 560, 620, 700}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]int(nil), tt.positions...)
+			fitted := append([]int(nil), tt.positions...)
+			fitPositionsToBounds(fitted, startX, width)
+
+			originalSpan := float64(original[len(original)-1] - original[0])
+			fittedSpan := float64(fitted[len(fitted)-1] - fitted[0])
+			for i, x := range fitted {
+				if x < startX || x > startX+width {
+					t.Errorf("position %d = %d outside [%d, %d]", i, x, startX, startX+width)
+				}
+				if i > 0 && x <= fitted[i-1] {
+					t.Errorf("order or spacing lost: %v", fitted)
+				}
+				want := float64(original[i]-original[0]) / originalSpan
+				if got := float64(x-fitted[0]) / fittedSpan; got < want-0.01 || got > want+0.01 {
+					t.Errorf("position %d at %.3f of the span, want %.3f (%v -> %v)", i, got, want, original, fitted)
+				}
+			}
+		})
+	}
+	// The solver needs 20 events 80px apart, far more than the usable width
+	config := getDefaultConfig()
+	events := burstEvents(20, "2024-01-01 08:00")
+	positions := calculateSmartPositions(events, startX, width, 80, config)
+	for i, x := range positions {
+		if x < startX || x > startX+width {
+			t.Errorf("solver position %d = %d outside [%d, %d]", i, x, startX, startX+width)
+		}
+		if i > 0 && x <= positions[i-1] {
+			t.Errorf("solver lost order or spacing: %v", positions)
+			break
+		}
+	}
+}
+
+func TestGetColumnStyleMixedDetailedAndSimpleColumns(t *testing.T) {
+	config := getDefaultConfig()
+	config.Columns.UseDetailedStyling = true
+	config.Columns.DisplayOrder = []string{"title", TimestampColumn, "notes"}
+	config.Columns.DetailedColumns = []ColumnStyle{
+		{Name: "Title", FontSize: 20, Color: "#ff0000"},
+		{Name: TimestampColumn, FontFamily: "Courier New, monospace", FontWeight: "bold", FontSize: 9},
+	}
+
+	tests := []struct {
+		column string
+		want   ColumnStyle
+	}{
+		{
+			// Detailed fields win; the rest come from the simple styling
+			column: "title",
+			want: ColumnStyle{FontFamily: config.Font.Family, FontSize: 20, FontWeight: "normal",
+				Color: "#ff0000", CSSClass: getElementClassName("title"), WrapWidth: DefaultWrapWidth},
+		},
+		{
+			column: TimestampColumn,
+			want: ColumnStyle{FontFamily: "Courier New, monospace", FontSize: 9, FontWeight: "bold",
+				Color: config.Colors.Text, CSSClass: getElementClassName(TimestampColumn), WrapWidth: DefaultWrapWidth},
+		},
+		{
+			// Only in display_order: plain simple styling
+			column: "notes",
+			want:   simpleColumnStyle("notes", config),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.column, func(t *testing.T) {
+			got := getColumnStyle(tt.column, config)
+			if got.FontFamily != tt.want.FontFamily || got.FontSize != tt.want.FontSize || got.FontWeight != tt.want.FontWeight ||
+				got.Color != tt.want.Color || got.CSSClass != tt.want.CSSClass || got.WrapWidth != tt.want.WrapWidth {
+				t.Errorf("getColumnStyle(%q) = %+v, want %+v", tt.column, got, tt.want)
+			}
+		})
+	}
+
+	// With no detailed entries at all, display_order and the simple styling apply
+	config.Columns.DetailedColumns = nil
+	order := getColumnOrder(config)
+	if strings.Join(order, ",") != strings.Join(config.Columns.DisplayOrder, ",") {
+		t.Errorf("getColumnOrder = %v, want display_order %v", order, config.Columns.DisplayOrder)
+	}
+	for _, column := range order {
+		if got, want := getColumnStyle(column, config), simpleColumnStyle(column, config); got.FontSize != want.FontSize || got.Color != want.Color {
+			t.Errorf("getColumnStyle(%q) = %+v, want simple styling %+v", column, got, want)
+		}
+	}
+}
+
+func TestParseCSVFromKeepsEqualTimestampsInFileOrder(t *testing.T) {
+	input := "timestamp,title\n" +
+		"2024-01-02 09:00,Later\n" +
+		"2024-01-01 09:00,Tie A\n" +
+		"2024-01-01 09:00,Tie B\n" +
+		"2024-01-01 09:00,Tie C\n" +
+		"2024-01-01 09:00,Tie D\n" +
+		"2024-01-01 09:00,Tie E\n" +
+		"2024-01-01 08:00,Earliest\n"
+	want := []string{"Earliest", "Tie A", "Tie B", "Tie C", "Tie D", "Tie E", "Later"}
+
+	for run := 0; run < 20; run++ {
+		events, err := parseCSVFrom(strings.NewReader(input), "utf-8", getDefaultConfig())
+		if err != nil {
+			t.Fatalf("parseCSVFrom: %v", err)
+		}
+		for i, event := range events {
+			if event.Data["title"] != want[i] {
+				t.Fatalf("run %d: event %d is %q, want order %v", run, i, event.Data["title"], want)
+			}
+		}
+	}
+}
+
+func TestGenerateSVGToRejectsDegenerateLayouts(t *testing.T) {
+	events := burstEvents(2, "2024-01-01 08:00")
+	tests := []struct {
+		name    string
+		modify  func(*Config)
+		wantErr string
+	}{
+		{name: "valid", modify: func(*Config) {}},
+		{name: "width equals margins and buffers", modify: func(c *Config) { c.Layout.Width = 300 }, wantErr: "layout.width 300 leaves no room"},
+		{name: "width below margins", modify: func(c *Config) { c.Layout.Width = 150 }, wantErr: "layout.width 150 leaves no room"},
+		{name: "negative width", modify: func(c *Config) { c.Layout.Width = -10 }, wantErr: "layout.width -10 leaves no room"},
+		{name: "height equals margins", modify: func(c *Config) { c.Layout.Height = 200 }, wantErr: "layout.height 200 leaves no room"},
+		{name: "zero height", modify: func(c *Config) { c.Layout.Height = 0 }, wantErr: "layout.height 0 leaves no room"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := getDefaultConfig()
+			config.Layout.MarginLeft, config.Layout.MarginRight = 100, 100
+			config.Layout.MarginTop, config.Layout.MarginBottom = 100, 100
+			config.Timeline.HorizontalBuffer = 50
+			tt.modify(&config)
+
+			var svg bytes.Buffer
+			err := GenerateSVGTo(&svg, events, config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("GenerateSVGTo: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+			}
+			if svg.Len() != 0 {
+				t.Errorf("wrote %d bytes of SVG for an invalid layout", svg.Len())
+			}
+		})
+	}
+}
+
+func TestBelowLineTextStacksOutward(t *testing.T) {
+	config := getDefaultConfig()
+	config.Columns.DisplayOrder = []string{"title", "owner", "notes"}
+	events := []TimelineEvent{
+		testEvent("2024-01-01 08:00", "Below title", "Below notes"),
+		testEvent("2024-01-02 08:00", "Above title", "Above notes"),
+	}
+	events[0].Data["owner"] = "Below owner"
+	events[1].Data["owner"] = "Above owner"
+	if !eventAbove(0, config) || eventAbove(1, config) {
+		t.Fatalf("expected event 0 below the line and event 1 above it")
+	}
+
+	var svg bytes.Buffer
+	if err := GenerateSVGTo(&svg, events, config); err != nil {
+		t.Fatalf("GenerateSVGTo: %v", err)
+	}
+	textY := func(text string) int {
+		out := svg.String()
+		end := strings.Index(out, ">"+text+"</text>")
+		if end < 0 {
+			t.Fatalf("SVG has no text %q", text)
+		}
+		start := strings.LastIndex(out[:end], "<text ")
+		var x, y int
+		if _, err := fmt.Sscanf(out[start:end], `<text x="%d" y="%d"`, &x, &y); err != nil {
+			t.Fatalf("reading y of %q: %v", text, err)
+		}
+		return y
+	}
+
+	lineY := timelineAxisY(config)
+	title, owner, notes := textY("Below title"), textY("Below owner"), textY("Below notes")
+	if !(lineY < title && title < owner && owner < notes) {
+		t.Errorf("below the line at y=%d: title %d, owner %d, notes %d; want each row further down", lineY, title, owner, notes)
+	}
+	title, owner, notes = textY("Above title"), textY("Above owner"), textY("Above notes")
+	if !(lineY > title && title > owner && owner > notes) {
+		t.Errorf("above the line at y=%d: title %d, owner %d, notes %d; want each row further up", lineY, title, owner, notes)
+	}
+}
+
+func TestLinkEventIconsResolvesAgainstConfigAndOutput(t *testing.T) {
+	config := getDefaultConfig()
+	config.BaseDir = "configs"
+	config.OutputDir = "out/svg"
+	config.Icons = []EventIcon{
+		{Value: "local", Image: "icons/github.svg"},
+		{Value: "url", Image: "https://example.com/logo.png"},
+		{Value: "absolute", Image: "/srv/icons/logo.svg"},
+	}
+
+	want := []string{"../../configs/icons/github.svg", "https://example.com/logo.png", "/srv/icons/logo.svg"}
+	for i, icon := range linkEventIcons(config) {
+		if icon.Image != want[i] {
+			t.Errorf("icon %q linked as %q, want %q", icon.Value, icon.Image, want[i])
+		}
+	}
+	if config.Icons[0].Image != "icons/github.svg" {
+		t.Errorf("linkEventIcons modified the config's icons list")
+	}
+}
+
+func TestStackedEventsWithOneTimestampStayOnCanvas(t *testing.T) {
+	config := getDefaultConfig()
+	config.Timeline.SameTime = "stack"
+	events := []TimelineEvent{
+		testEvent("2024-01-01 10:00", "First", "First notes"),
+		testEvent("2024-01-01 10:00", "Second", "Second notes"),
+		testEvent("2024-01-01 10:00", "Third", "Third notes"),
+	}
+
+	var svg bytes.Buffer
+	if err := GenerateSVGTo(&svg, events, config); err != nil {
+		t.Fatalf("GenerateSVGTo: %v", err)
+	}
+	out := svg.String()
+	for _, title := range []string{"First", "Second", "Third"} {
+		end := strings.Index(out, ">"+title+"</text>")
+		if end < 0 {
+			t.Fatalf("SVG has no text %q", title)
+		}
+		start := strings.LastIndex(out[:end], "<text ")
+		var x, y int
+		if _, err := fmt.Sscanf(out[start:end], `<text x="%d" y="%d"`, &x, &y); err != nil {
+			t.Fatalf("reading x of %q: %v", title, err)
+		}
+		if want := config.Layout.Width / 2; x != want {
+			t.Errorf("%s drawn at x=%d, want the middle of the canvas at %d", title, x, want)
+		}
+	}
+}
+
+func TestStackedEventsDoNotOverlapNearbyEvents(t *testing.T) {
+	config := getDefaultConfig()
+	config.Timeline.SameTime = "stack"
+	events := []TimelineEvent{
+		testEvent("2024-01-01 08:00", "Alpha", "First event"),
+		testEvent("2024-01-01 10:00", "Bravo", "Stacked one"),
+		testEvent("2024-01-01 10:00", "Charlie", "Stacked two"),
+		testEvent("2024-01-01 10:01", "Delta", "Right after the stack"),
+		testEvent("2024-01-01 12:00", "Echo", "Last event"),
+	}
+
+	var svg bytes.Buffer
+	if err := GenerateSVGTo(&svg, events, config); err != nil {
+		t.Fatalf("GenerateSVGTo: %v", err)
+	}
+	out := svg.String()
+
+	// box returns the area a drawn text line covers, from its x, baseline and style
+	type box struct{ left, right, top, bottom int }
+	textBox := func(text, column string) box {
+		end := strings.Index(out, ">"+text+"</text>")
+		if end < 0 {
+			t.Fatalf("SVG has no text %q", text)
+		}
+		start := strings.LastIndex(out[:end], "<text ")
+		var x, y int
+		if _, err := fmt.Sscanf(out[start:end], `<text x="%d" y="%d"`, &x, &y); err != nil {
+			t.Fatalf("reading position of %q: %v", text, err)
+		}
+		style := getColumnStyle(column, config)
+		width := elementTextWidth(text, style, config)
+		return box{left: x - width/2, right: x + width/2, top: y - style.FontSize, bottom: y}
+	}
+
+	boxes := make([][]box, len(events))
+	for i, event := range events {
+		boxes[i] = []box{textBox(event.Data["title"], "title"), textBox(event.Data["notes"], "notes")}
+	}
+	for _, stackedIdx := range []int{1, 2} {
+		for _, a := range boxes[stackedIdx] {
+			for _, b := range boxes[3] {
+				if a.right > b.left && a.left < b.right && a.bottom > b.top && a.top < b.bottom {
+					t.Errorf("text of stacked event %d at %+v overlaps the nearby event's text at %+v", stackedIdx, a, b)
+				}
+			}
+		}
+	}
+	if x1, x2 := boxes[1][0].left+boxes[1][0].right, boxes[2][0].left+boxes[2][0].right; x1 != x2 {
+		t.Errorf("stacked events drawn at different x: %d and %d", x1/2, x2/2)
+	}
+}
+
+func TestBoundingBoxesFollowTheArc(t *testing.T) {
+	config := getDefaultConfig()
+	event := testEvent("2024-01-01 08:00", "Title", "Notes")
+	centreX := config.Layout.MarginLeft + (config.Layout.Width-config.Layout.MarginLeft-config.Layout.MarginRight)/2
+	timelineY := timelineAxisY(config)
+	straight := calculateEventBoundingBox(event, centreX, timelineY, 40, 1, config)
+
+	config.Timeline.Shape = "arc"
+	if got := arcHeight(config); got != 60 {
+		t.Fatalf("arcHeight with arc_height unset = %d, want 60", got)
+	}
+	arc := calculateEventBoundingBox(event, centreX, timelineY, 40, 1, config)
+	if arc.Top != straight.Top-60 || arc.Bottom != straight.Bottom-60 {
+		t.Errorf("box at the apex spans y %d-%d, want the straight-line box %d-%d raised by 60",
+			arc.Top, arc.Bottom, straight.Top, straight.Bottom)
+	}
+	if arc.Left != straight.Left {
+		t.Errorf("box at the apex starts at x=%d, want %d as the normal is vertical there", arc.Left, straight.Left)
+	}
+}
+
+func TestEventMarkerShapeFromColumn(t *testing.T) {
+	config := getDefaultConfig()
+	config.EventMarker.Shape = "circle"
+	config.Columns.ShapeColumn = "Kind"
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "diamond", want: "diamond"},
+		{value: " Square ", want: "square"},
+		{value: "", want: "circle"},
+		{value: "hexagon", want: "circle"},
+	}
+	for _, tt := range tests {
+		event := testEvent("2024-01-01 08:00", "Title", "")
+		event.Data["kind"] = tt.value
+		if got := eventMarkerShape(event, config); got != tt.want {
+			t.Errorf("eventMarkerShape(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFormatNumberGroupsOnlyPlainDecimals(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "1234567", want: "1,234,567"},
+		{text: "-1234567.25", want: "-1,234,567.25"},
+		{text: "+1234", want: "1,234"},
+		{text: "999", want: "999"},
+		{text: "Inf", want: "Inf"},
+		{text: "-Infinity", want: "-Infinity"},
+		{text: "NaN", want: "NaN"},
+		{text: "0x1F4A0", want: "0x1F4A0"},
+		{text: "1e10", want: "1e10"},
+		{text: "1_000_000", want: "1_000_000"},
+		{text: "1234.", want: "1234."},
+		{text: ".5", want: ".5"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.text, "grouped"); got != tt.want {
+			t.Errorf("formatNumber(%q, grouped) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestUTCOffsetLabel(t *testing.T) {
+	tests := []struct {
+		timestamp string
+		want      string
+	}{
+		{timestamp: "2024-01-01T10:00:00Z", want: "UTC"},
+		{timestamp: "2024-01-01T10:00:00+02:00", want: "+0200"},
+		{timestamp: "2024-01-01T10:00:00-05:30", want: "-0530"},
+	}
+	for _, tt := range tests {
+		parsed, err := parseTimestamp(tt.timestamp)
+		if err != nil {
+			t.Fatalf("parseTimestamp(%q): %v", tt.timestamp, err)
+		}
+		if got := utcOffsetLabel(parsed); got != tt.want {
+			t.Errorf("utcOffsetLabel(%q) = %q, want %q", tt.timestamp, got, tt.want)
+		}
+	}
+}
+
+func TestAutoContrastRecolorsLinesAndMarkers(t *testing.T) {
+	config := getDefaultConfig()
+	config.Colors.Background = "#111111"
+	config.Colors.AutoContrast = true
+	config.Timeline.ShowAxis = true
+	events := burstEvents(3, "2024-01-01 08:00")
+
+	var svg bytes.Buffer
+	if err := GenerateSVGTo(&svg, events, config); err != nil {
+		t.Fatalf("GenerateSVGTo: %v", err)
+	}
+	for _, attr := range []string{"stroke", "fill"} {
+		for _, part := range strings.Split(svg.String(), attr+`="#`)[1:] {
+			color := "#" + part[:strings.Index(part, `"`)]
+			if color != "#ffffff" && color != config.Colors.Background {
+				t.Errorf("found %s=%q on a dark background, want #ffffff", attr, color)
+			}
+		}
+	}
+}
+
+func TestEventCountStaysOnCanvas(t *testing.T) {
+	for _, position := range []string{"top-left", "bottom-left", "top-right", "bottom-right"} {
+		config := getDefaultConfig()
+		config.Layout.MarginLeft, config.Layout.MarginRight = 10, 10
+		config.Timeline.CountPosition = position
+
+		var svg bytes.Buffer
+		drawEventCount(&svg, 12, 300, config.Layout.MarginLeft, config.Layout.Width-config.Layout.MarginRight, config)
+		var x, y int
+		var anchor string
+		if _, err := fmt.Sscanf(svg.String(), `<text x="%d" y="%d" text-anchor=%q`, &x, &y, &anchor); err != nil {
+			t.Fatalf("%s: reading label position from %q: %v", position, svg.String(), err)
+		}
+		width := estimateTextWidth("12 events", maxInt(config.Font.Size-2, 6))
+		left, right := x-width, x
+		if anchor == "start" {
+			left, right = x, x+width
+		}
+		if left < 0 || right > config.Layout.Width {
+			t.Errorf("%s: label spans x %d-%d, outside the %d px canvas", position, left, right, config.Layout.Width)
+		}
+	}
+}
+
+func TestDebugSnippetKeepsRunesWhole(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{text: "short", want: "short"},
+		{text: strings.Repeat("a", 40), want: strings.Repeat("a", 30)},
+		{text: strings.Repeat("é", 40), want: strings.Repeat("é", 30)},
+		{text: strings.Repeat("a", 29) + "日本", want: strings.Repeat("a", 29) + "日"},
+	}
+	for _, tt := range tests {
+		if got := debugSnippet(tt.text); got != tt.want {
+			t.Errorf("debugSnippet(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}